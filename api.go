@@ -0,0 +1,476 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// apiMaxBodyBytes 接口请求体大小上限
+const apiMaxBodyBytes = 10 << 20
+
+// APISettings 本地REST接口配置（只监听127.0.0.1）
+type APISettings struct {
+	Enabled bool   `json:"enabled"` // 是否启用
+	Port    int    `json:"port"`    // 监听端口
+	Token   string `json:"token"`   // 访问令牌（Authorization: Bearer <token>）
+}
+
+// APIStatus 本地REST接口运行状态
+type APIStatus struct {
+	Enabled bool   `json:"enabled"`
+	Running bool   `json:"running"`
+	Address string `json:"address"` // 接口地址，如 http://127.0.0.1:17890/api/v1
+	Error   string `json:"error"`   // 启动失败的原因
+}
+
+// apiTaskRequest 创建/更新任务的请求体（更新时未提供的字段保持不变）
+type apiTaskRequest struct {
+	Name             string           `json:"name"`
+	URL              string           `json:"url"`
+	Method           string           `json:"method"`
+	HeadersText      string           `json:"headersText"`
+	Data             string           `json:"data"`
+	Times            int              `json:"times"`
+	Threads          int              `json:"threads"`
+	DelayMin         int              `json:"delayMin"`
+	DelayMax         int              `json:"delayMax"`
+	Tags             []string         `json:"tags"`
+	CronExpr         string           `json:"cronExpr"`
+	SuccessCondition SuccessCondition `json:"successCondition"`
+}
+
+// defaultAPISettings 默认接口配置（默认关闭）
+func defaultAPISettings() APISettings {
+	return APISettings{
+		Enabled: false,
+		Port:    17890,
+	}
+}
+
+// validate 校验接口配置
+func (s APISettings) validate() error {
+	if s.Port <= 0 || s.Port > 65535 {
+		return fmt.Errorf("接口端口必须在1-65535之间")
+	}
+	if s.Token != "" && len(s.Token) < 16 {
+		return fmt.Errorf("接口令牌长度不能少于16个字符")
+	}
+	return nil
+}
+
+// generateAPIToken 生成随机访问令牌
+func generateAPIToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// GetAPIStatus 获取本地REST接口状态
+func (a *App) GetAPIStatus() APIStatus {
+	settings := a.getSettings().API
+	running, err := a.apiService.status()
+
+	return APIStatus{
+		Enabled: settings.Enabled,
+		Running: running,
+		Address: fmt.Sprintf("http://127.0.0.1:%d/api/v1", settings.Port),
+		Error:   err,
+	}
+}
+
+// RegenerateAPIToken 重新生成接口令牌，旧令牌立即失效
+func (a *App) RegenerateAPIToken() string {
+	token, err := generateAPIToken()
+	if err != nil {
+		return fmt.Sprintf("错误：生成令牌失败：%v", err)
+	}
+
+	settings := a.getSettings()
+	settings.API.Token = token
+	if err := a.applySettings(settings); err != nil {
+		return fmt.Sprintf("错误：%v", err)
+	}
+
+	return token
+}
+
+// startAPIServer 按设置启动接口服务（桌面程序和守护进程调用）
+func (a *App) startAPIServer() {
+	settings := a.getSettings().API
+	a.apiService.start(settings.Enabled, fmt.Sprintf("127.0.0.1:%d", settings.Port), a.apiHandler)
+}
+
+// reloadAPIServer 设置变化后重启接口服务（当前进程未提供接口服务时忽略）
+func (a *App) reloadAPIServer() {
+	if !a.apiService.isActive() {
+		return
+	}
+
+	a.apiService.stop()
+	a.startAPIServer()
+}
+
+// apiHandler 注册接口路由
+func (a *App) apiHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	// 版本与状态
+	mux.HandleFunc("GET /api/v1/status", func(w http.ResponseWriter, r *http.Request) {
+		writeAPIJSON(w, http.StatusOK, map[string]interface{}{
+			"version":        a.GetVersionInfo(),
+			"daemon":         a.GetDaemonStatus(),
+			"taskCount":      a.GetTaskCount(),
+			"scheduledTasks": a.GetScheduledTasks(),
+		})
+	})
+
+	// 任务
+	mux.HandleFunc("GET /api/v1/tasks", a.apiListTasks)
+	mux.HandleFunc("POST /api/v1/tasks", a.apiCreateTask)
+	mux.HandleFunc("GET /api/v1/tasks/{id}", a.apiGetTask)
+	mux.HandleFunc("PUT /api/v1/tasks/{id}", a.apiUpdateTask)
+	mux.HandleFunc("PATCH /api/v1/tasks/{id}", a.apiUpdateTask)
+	mux.HandleFunc("DELETE /api/v1/tasks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		name, err := a.deleteTask(r.PathValue("id"))
+		writeAPIResult(w, fmt.Sprintf("任务 '%s' 删除成功", name), err)
+	})
+
+	// 执行
+	mux.HandleFunc("POST /api/v1/tasks/{id}/run", func(w http.ResponseWriter, r *http.Request) {
+		task, err := a.startTask(r.PathValue("id"), TriggerAPI)
+		if err != nil {
+			writeAPIError(w, apiStatusForError(err), err.Error())
+			return
+		}
+		writeAPIJSON(w, http.StatusAccepted, map[string]string{"message": fmt.Sprintf("任务 '%s' 开始执行", task.Name)})
+	})
+	mux.HandleFunc("POST /api/v1/tasks/{id}/stop", func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := a.apiLookupTask(w, r.PathValue("id")); !ok {
+			return
+		}
+		writeAPIResult(w, "任务已停止", a.stopTask(r.PathValue("id")))
+	})
+	mux.HandleFunc("POST /api/v1/tasks/{id}/test", func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := a.apiLookupTask(w, r.PathValue("id")); !ok {
+			return
+		}
+		writeAPIJSON(w, http.StatusOK, a.TestTaskWithBackend(r.PathValue("id")))
+	})
+	mux.HandleFunc("GET /api/v1/tasks/{id}/progress", func(w http.ResponseWriter, r *http.Request) {
+		writeAPIJSON(w, http.StatusOK, a.GetTaskProgress(r.PathValue("id")))
+	})
+
+	// 定时调度
+	mux.HandleFunc("GET /api/v1/tasks/{id}/schedule", func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := a.apiLookupTask(w, r.PathValue("id")); !ok {
+			return
+		}
+		writeAPIJSON(w, http.StatusOK, a.GetTaskScheduleInfo(r.PathValue("id")))
+	})
+	mux.HandleFunc("POST /api/v1/tasks/{id}/schedule", func(w http.ResponseWriter, r *http.Request) {
+		task, err := a.enableSchedule(r.PathValue("id"))
+		if err != nil {
+			writeAPIError(w, apiStatusForError(err), err.Error())
+			return
+		}
+		writeAPIJSON(w, http.StatusOK, map[string]string{"message": fmt.Sprintf("任务 '%s' 已添加到定时调度", task.Name)})
+	})
+	mux.HandleFunc("DELETE /api/v1/tasks/{id}/schedule", func(w http.ResponseWriter, r *http.Request) {
+		task, ok := a.apiLookupTask(w, r.PathValue("id"))
+		if !ok {
+			return
+		}
+		writeAPIResult(w, fmt.Sprintf("任务 '%s' 已从定时调度中移除", task.Name), a.unscheduleTask(task.ID))
+	})
+	mux.HandleFunc("PUT /api/v1/tasks/{id}/schedule/options", func(w http.ResponseWriter, r *http.Request) {
+		var options ScheduleOptions
+		if !decodeAPIBody(w, r, &options) {
+			return
+		}
+		name, err := a.setTaskScheduleOptions(r.PathValue("id"), &options)
+		writeAPIResult(w, fmt.Sprintf("任务 '%s' 的调度选项设置成功", name), err)
+	})
+
+	mux.HandleFunc("GET /api/v1/tasks/{id}/schedule/history", func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := a.apiLookupTask(w, r.PathValue("id")); !ok {
+			return
+		}
+		limit := 0
+		if limitText := r.URL.Query().Get("limit"); limitText != "" {
+			parsed, err := strconv.Atoi(limitText)
+			if err != nil || parsed <= 0 {
+				writeAPIError(w, http.StatusBadRequest, "limit 必须是正整数")
+				return
+			}
+			limit = parsed
+		}
+		history := a.GetScheduleHistory(r.PathValue("id"), limit)
+		if history.Error != "" {
+			writeAPIError(w, http.StatusInternalServerError, history.Error)
+			return
+		}
+		writeAPIJSON(w, http.StatusOK, history)
+	})
+	mux.HandleFunc("GET /api/v1/schedule/timeline", func(w http.ResponseWriter, r *http.Request) {
+		timeline := a.GetScheduleTimeline(r.URL.Query().Get("start"), r.URL.Query().Get("end"))
+		if timeline.Error != "" {
+			writeAPIError(w, http.StatusBadRequest, timeline.Error)
+			return
+		}
+		writeAPIJSON(w, http.StatusOK, timeline)
+	})
+
+	// 日志
+	mux.HandleFunc("GET /api/v1/tasks/{id}/logs", a.apiTaskLogs)
+	mux.HandleFunc("DELETE /api/v1/tasks/{id}/logs", func(w http.ResponseWriter, r *http.Request) {
+		task, ok := a.apiLookupTask(w, r.PathValue("id"))
+		if !ok {
+			return
+		}
+		writeAPIResult(w, fmt.Sprintf("任务 '%s' 的日志已清空", task.Name), a.clearTaskLogs(task.ID))
+	})
+	mux.HandleFunc("GET /api/v1/logs/{logId}", func(w http.ResponseWriter, r *http.Request) {
+		executionLog := a.GetExecutionLog(r.PathValue("logId"))
+		if executionLog == nil {
+			writeAPIError(w, http.StatusNotFound, "执行日志不存在")
+			return
+		}
+		writeAPIJSON(w, http.StatusOK, executionLog)
+	})
+	mux.HandleFunc("GET /api/v1/logs/{logId}/stats", func(w http.ResponseWriter, r *http.Request) {
+		stats := a.GetExecutionStats(r.PathValue("logId"))
+		if stats == nil {
+			writeAPIError(w, http.StatusNotFound, "执行日志不存在")
+			return
+		}
+		writeAPIJSON(w, http.StatusOK, stats)
+	})
+
+	// 环境变量
+	mux.HandleFunc("GET /api/v1/variables", func(w http.ResponseWriter, r *http.Request) {
+		writeAPIJSON(w, http.StatusOK, a.GetEnvVariablesWithSeparator())
+	})
+	mux.HandleFunc("PUT /api/v1/variables/{key}", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := readAPIBody(w, r)
+		if !ok {
+			return
+		}
+		key := r.PathValue("key")
+		writeAPIResult(w, fmt.Sprintf("环境变量 '%s' 设置成功", key), a.setEnvVariable(key, string(body)))
+	})
+	mux.HandleFunc("DELETE /api/v1/variables/{key}", func(w http.ResponseWriter, r *http.Request) {
+		key := r.PathValue("key")
+		writeAPIResult(w, fmt.Sprintf("环境变量 '%s' 删除成功", key), a.deleteEnvVariable(key))
+	})
+
+	return a.apiAuthenticate(mux)
+}
+
+// apiAuthenticate 校验访问令牌（支持 Authorization: Bearer 和 X-API-Token）
+func (a *App) apiAuthenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("X-API-Token")
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			token = strings.TrimPrefix(auth, "Bearer ")
+		}
+
+		expected := a.getSettings().API.Token
+		if expected == "" || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+			writeAPIError(w, http.StatusUnauthorized, "访问令牌无效")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// apiListTasks 获取任务列表（按创建时间排序，可用 ?tag= 筛选）
+func (a *App) apiListTasks(w http.ResponseWriter, r *http.Request) {
+	tasks, err := a.tasksForExport(nil)
+	if err != nil {
+		tasks = []*Task{}
+	}
+
+	if tag := r.URL.Query().Get("tag"); tag != "" {
+		var filtered []*Task
+		for _, task := range tasks {
+			if containsString(task.Tags, tag) {
+				filtered = append(filtered, task)
+			}
+		}
+		tasks = filtered
+	}
+
+	if tasks == nil {
+		tasks = []*Task{}
+	}
+	writeAPIJSON(w, http.StatusOK, tasks)
+}
+
+// apiCreateTask 创建任务
+func (a *App) apiCreateTask(w http.ResponseWriter, r *http.Request) {
+	request := apiTaskRequest{Method: "GET", Times: 1, Threads: 1}
+	if !decodeAPIBody(w, r, &request) {
+		return
+	}
+
+	task, err := a.createTask(request.Name, request.URL, request.Method, request.HeadersText, request.Data,
+		request.Times, request.Threads, request.DelayMin, request.DelayMax, request.Tags, request.CronExpr, request.SuccessCondition)
+	if err != nil {
+		writeAPIError(w, apiStatusForError(err), err.Error())
+		return
+	}
+
+	writeAPIJSON(w, http.StatusCreated, task)
+}
+
+// apiGetTask 获取任务详情
+func (a *App) apiGetTask(w http.ResponseWriter, r *http.Request) {
+	if task, ok := a.apiLookupTask(w, r.PathValue("id")); ok {
+		writeAPIJSON(w, http.StatusOK, task)
+	}
+}
+
+// apiUpdateTask 更新任务（未提供的字段保持原值）
+func (a *App) apiUpdateTask(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	task, ok := a.apiLookupTask(w, taskID)
+	if !ok {
+		return
+	}
+
+	request := apiTaskRequest{
+		Name:             task.Name,
+		URL:              task.URL,
+		Method:           task.Method,
+		HeadersText:      task.HeadersText,
+		Data:             task.Data,
+		Times:            task.Times,
+		Threads:          task.Threads,
+		DelayMin:         task.DelayMin,
+		DelayMax:         task.DelayMax,
+		Tags:             task.Tags,
+		CronExpr:         task.CronExpr,
+		SuccessCondition: task.SuccessCondition,
+	}
+
+	if !decodeAPIBody(w, r, &request) {
+		return
+	}
+	if request.Name == "" || request.URL == "" {
+		writeAPIError(w, http.StatusBadRequest, "任务名称和URL不能为空")
+		return
+	}
+
+	err := a.updateTask(taskID, request.Name, request.URL, request.Method, request.HeadersText, request.Data,
+		request.Times, request.Threads, request.DelayMin, request.DelayMax, request.Tags, request.CronExpr, request.SuccessCondition)
+	if err != nil {
+		writeAPIError(w, apiStatusForError(err), err.Error())
+		return
+	}
+
+	a.apiGetTask(w, r)
+}
+
+// apiTaskLogs 获取任务的执行记录（按时间倒序，可用 ?limit= 限制条数）
+func (a *App) apiTaskLogs(w http.ResponseWriter, r *http.Request) {
+	if _, ok := a.apiLookupTask(w, r.PathValue("id")); !ok {
+		return
+	}
+
+	entries := a.GetTaskLogEntries(r.PathValue("id"))
+	if limitText := r.URL.Query().Get("limit"); limitText != "" {
+		limit, err := strconv.Atoi(limitText)
+		if err != nil || limit <= 0 {
+			writeAPIError(w, http.StatusBadRequest, "limit 必须是正整数")
+			return
+		}
+		if limit < len(entries) {
+			entries = entries[:limit]
+		}
+	}
+
+	writeAPIJSON(w, http.StatusOK, entries)
+}
+
+// apiLookupTask 查找任务并返回副本（执行中会更新缓存中任务的运行状态），不存在时返回404
+func (a *App) apiLookupTask(w http.ResponseWriter, taskID string) (*Task, bool) {
+	a.cacheMutex.RLock()
+	task, exists := a.tasksCache[taskID]
+	var copied Task
+	if exists {
+		copied = *task
+	}
+	a.cacheMutex.RUnlock()
+
+	if !exists {
+		writeAPIError(w, http.StatusNotFound, "任务不存在")
+		return nil, false
+	}
+	return &copied, true
+}
+
+// apiStatusForError 根据内部方法返回的错误确定HTTP状态码
+func apiStatusForError(err error) int {
+	var input *inputError
+	switch {
+	case errors.Is(err, errTaskNotFound), errors.Is(err, errLogsNotFound), errors.Is(err, errVariableNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errTaskRunning), errors.Is(err, errTaskNotRunning), errors.Is(err, errTaskNotScheduled):
+		return http.StatusConflict
+	case errors.As(err, &input):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// readAPIBody 读取请求体
+func readAPIBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	var body json.RawMessage
+	if !decodeAPIBody(w, r, &body) {
+		return nil, false
+	}
+	return body, true
+}
+
+// decodeAPIBody 解析JSON请求体
+func decodeAPIBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, apiMaxBodyBytes)
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("数据格式错误：%v", err))
+		return false
+	}
+	return true
+}
+
+// writeAPIResult 输出内部方法的执行结果（出错时按错误类型返回状态码）
+func writeAPIResult(w http.ResponseWriter, message string, err error) {
+	if err != nil {
+		writeAPIError(w, apiStatusForError(err), err.Error())
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, map[string]string{"message": message})
+}
+
+// writeAPIError 输出错误信息
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	writeAPIJSON(w, status, map[string]string{"error": message})
+}
+
+// writeAPIJSON 输出JSON响应
+func writeAPIJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		fmt.Printf("输出接口响应失败: %v\n", err)
+	}
+}
@@ -2,17 +2,22 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math/rand"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -36,25 +41,46 @@ type VersionInfo struct {
 // App - 重新设计的应用结构，优化性能
 type App struct {
 	ctx           context.Context
-	runningTasks  map[string]*TaskProgress
+	runningTasks  map[string][]*TaskProgress // 执行中的运行（允许并发执行时同一任务可能有多个）
+	queuedRuns    map[string]int64           // 等待上一次执行结束的定时执行（值为触发记录ID）
 	taskMutex     sync.RWMutex
 	cronScheduler *cron.Cron
 	cronJobs      map[string]cron.EntryID
 	cronMutex     sync.RWMutex
+	fireStates    map[string]*scheduleFireState // 各任务最近一次定时触发的时间
+	fireMutex     sync.Mutex
 	// 添加缓存机制
 	tasksCache    map[string]*Task
 	cacheMutex    sync.RWMutex
 	lastCacheTime time.Time
+	tasksSnapshot []byte // 上次与磁盘同步时的任务文件内容（用于合并其他进程的修改，受cacheMutex保护）
 	// 日志管理
-	taskLogs      map[string][]TaskLogEntry // 任务级别日志
-	executionLogs map[string]ExecutionLog   // 执行详细日志
-	logMutex      sync.RWMutex              // 日志锁
+	taskLogs              map[string][]TaskLogEntry // 任务级别日志
+	executionLogs         map[string]ExecutionLog   // 执行详细日志
+	logMutex              sync.RWMutex              // 日志锁
+	logFileMutex          sync.Mutex                // 日志文件写入锁（避免并发写同一文件）
+	pendingSaves          sync.WaitGroup            // 后台保存操作（退出前等待完成）
+	taskLogsSnapshot      map[string]bool           // 上次与磁盘同步时的任务日志ID（受logMutex保护）
+	executionLogsSnapshot map[string]bool           // 上次与磁盘同步时的详细日志ID（受logMutex保护）
+	taskLogsFile          os.FileInfo               // 上次同步时的任务日志文件（受logFileMutex保护）
+	executionLogsFile     os.FileInfo               // 上次同步时的详细日志文件（受logFileMutex保护）
 	// 环境变量管理
 	envVariables map[string]EnvVariableData // 环境变量存储（支持分隔符）
 	envMutex     sync.RWMutex               // 环境变量锁
 	// 数据库管理
 	db      *sql.DB      // SQLite数据库连接
 	dbMutex sync.RWMutex // 数据库操作锁
+	// 全局设置
+	settings      AppSettings  // 全局设置
+	settingsMutex sync.RWMutex // 设置锁
+	// 后台任务
+	shutdown          chan struct{} // 应用关闭时通知后台协程退出
+	lastCleanupReport CleanupReport // 最近一次清理报告（受logMutex保护）
+	isDaemon          bool          // 是否以守护进程模式运行
+	// 本地REST接口和指标接口
+	apiService     httpService
+	metricsService httpService
+	metrics        *runMetrics // 执行和请求指标
 }
 
 // SuccessCondition - 成功条件配置
@@ -63,6 +89,7 @@ type SuccessCondition struct {
 	JsonPath      string `json:"jsonPath"` // JSON路径（用于JSON路径判断）
 	Operator      string `json:"operator"` // equals, not_equals, contains, not_contains, response_contains, response_not_contains, response_equals, response_not_equals
 	ExpectedValue string `json:"expectedValue"`
+	StatusCodes   []int  `json:"statusCodes"` // 按HTTP状态码判断时期望的状态码（为空时接受任意2xx）
 }
 
 // EnvVariableData - 环境变量数据结构（支持分隔符）
@@ -73,26 +100,33 @@ type EnvVariableData struct {
 
 // Task - 简化的任务结构，优化内存使用
 type Task struct {
-	ID               string            `json:"id"`
-	Name             string            `json:"name"`
-	URL              string            `json:"url"`
-	Method           string            `json:"method"`
-	Headers          map[string]string `json:"headers"`     // 使用map，更高效
-	HeadersText      string            `json:"headersText"` // 用于前端显示和编辑
-	Data             string            `json:"data"`
-	Times            int               `json:"times"`
-	Threads          int               `json:"threads"`
-	DelayMin         int               `json:"delayMin"`
-	DelayMax         int               `json:"delayMax"`
-	Tags             []string          `json:"tags"`
-	CronExpr         string            `json:"cronExpr"`
-	SuccessCondition SuccessCondition  `json:"successCondition"` // 成功条件配置
-	CreatedAt        int64             `json:"createdAt"`        // 时间戳，更高效
-	UpdatedAt        int64             `json:"updatedAt"`
-	IsRunning        bool              `json:"isRunning"`
-	LastRunTime      string            `json:"lastRunTime"`   // 最后执行时间
-	LastRunStatus    string            `json:"lastRunStatus"` // 最后执行状态: success, failed, running
-	LastRunResult    string            `json:"lastRunResult"` // 最后执行结果描述
+	ID               string              `json:"id"`
+	Name             string              `json:"name"`
+	URL              string              `json:"url"`
+	Method           string              `json:"method"`
+	Headers          map[string]string   `json:"headers"`     // 使用map，更高效
+	HeadersText      string              `json:"headersText"` // 用于前端显示和编辑
+	Data             string              `json:"data"`
+	Times            int                 `json:"times"`
+	Threads          int                 `json:"threads"`
+	DelayMin         int                 `json:"delayMin"`
+	DelayMax         int                 `json:"delayMax"`
+	Tags             []string            `json:"tags"`
+	CronExpr         string              `json:"cronExpr"`
+	SuccessCondition SuccessCondition    `json:"successCondition"` // 成功条件配置
+	CreatedAt        int64               `json:"createdAt"`        // 时间戳，更高效
+	UpdatedAt        int64               `json:"updatedAt"`
+	IsRunning        bool                `json:"isRunning"`
+	LastRunTime      string              `json:"lastRunTime"`   // 最后执行时间
+	LastRunStatus    string              `json:"lastRunStatus"` // 最后执行状态: success, failed, running
+	LastRunResult    string              `json:"lastRunResult"` // 最后执行结果描述
+	Retention        *RetentionPolicy    `json:"retention"`     // 日志保留策略（为空时使用全局策略）
+	Capture          *CaptureConfig      `json:"capture"`       // 请求/响应采集配置（为空时使用全局配置）
+	Insecure         bool                `json:"insecure"`      // 跳过TLS证书校验
+	Proxy            string              `json:"proxy"`         // 代理地址（如 http://127.0.0.1:8080、socks5://127.0.0.1:1080）
+	Source           *TaskSource         `json:"source"`        // 导入来源（用于重新同步）
+	Notifications    *NotificationConfig `json:"notifications"` // 通知配置（为空时使用全局配置）
+	Schedule         *ScheduleOptions    `json:"schedule"`      // 定时调度选项（为空时使用默认值）
 }
 
 // TaskProgress - 简化的进度结构
@@ -101,6 +135,10 @@ type TaskProgress struct {
 	Total     int   `json:"total"`
 	StartTime int64 `json:"startTime"`
 	IsRunning bool  `json:"isRunning"`
+
+	ctx    context.Context    // 本次运行的上下文（停止时取消）
+	cancel context.CancelFunc // 停止本次运行
+	done   chan struct{}      // 本次运行结束后关闭
 }
 
 // TaskList - 任务列表响应
@@ -109,15 +147,46 @@ type TaskList struct {
 	Total int              `json:"total"`
 }
 
+// 内部方法返回的错误（本地接口据此返回对应的HTTP状态码）
+var (
+	errTaskNotFound     = errors.New("错误：任务不存在")
+	errTaskRunning      = errors.New("错误：任务正在运行中")
+	errTaskNotRunning   = errors.New("错误：任务未在运行")
+	errTaskNotScheduled = errors.New("任务没有定时调度")
+	errLogsNotFound     = errors.New("任务日志不存在")
+	errVariableNotFound = errors.New("错误：变量不存在")
+)
+
+// inputError 参数错误
+type inputError struct {
+	message string
+}
+
+func (e *inputError) Error() string {
+	return e.message
+}
+
+// inputErrorf 创建参数错误
+func inputErrorf(format string, args ...interface{}) error {
+	return &inputError{message: fmt.Sprintf(format, args...)}
+}
+
 // NewApp creates a new App application struct
 func NewApp() *App {
 	app := &App{
-		runningTasks:  make(map[string]*TaskProgress),
-		cronJobs:      make(map[string]cron.EntryID),
-		tasksCache:    make(map[string]*Task),
-		taskLogs:      make(map[string][]TaskLogEntry),
-		executionLogs: make(map[string]ExecutionLog),
-		envVariables:  make(map[string]EnvVariableData),
+		runningTasks:   make(map[string][]*TaskProgress),
+		queuedRuns:     make(map[string]int64),
+		cronJobs:       make(map[string]cron.EntryID),
+		fireStates:     make(map[string]*scheduleFireState),
+		tasksCache:     make(map[string]*Task),
+		taskLogs:       make(map[string][]TaskLogEntry),
+		executionLogs:  make(map[string]ExecutionLog),
+		envVariables:   make(map[string]EnvVariableData),
+		settings:       defaultSettings(),
+		shutdown:       make(chan struct{}),
+		apiService:     httpService{name: "本地接口"},
+		metricsService: httpService{name: "指标接口"},
+		metrics:        newRunMetrics(),
 		// 支持秒字段的cron调度器
 		cronScheduler: cron.New(cron.WithSeconds()),
 	}
@@ -138,12 +207,21 @@ func (a *App) OnStartup(ctx context.Context) {
 
 // OnDomReady is called after front-end resources have been loaded
 func (a *App) OnDomReady(ctx context.Context) {
-	// 预加载任务数据
-	go a.preloadTasks()
-	// 恢复定时任务状态
-	go a.restoreScheduledTasks()
-	// 加载历史日志数据
-	go a.loadHistoryLogs()
+	// 预加载任务数据，完成后启动本地REST接口和指标接口（如已启用）
+	go func() {
+		a.preloadTasks()
+		a.startHTTPServices()
+	}()
+	// 恢复定时任务状态，之后跟随守护进程的启停交接调度
+	go func() {
+		a.restoreScheduledTasks()
+		a.watchDaemon()
+	}()
+	// 加载历史日志数据，完成后启动后台日志清理
+	go func() {
+		a.loadHistoryLogs()
+		a.logCleanupLoop()
+	}()
 	// 加载环境变量
 	go a.loadEnvVariables()
 }
@@ -151,18 +229,23 @@ func (a *App) OnDomReady(ctx context.Context) {
 // OnShutdown is called when the app is shutting down
 func (a *App) OnShutdown(ctx context.Context) {
 	a.cronScheduler.Stop()
+	close(a.shutdown)
+	a.stopHTTPServices()
+	a.flushScheduleFires()
+	a.waitForPendingSaves()
 	if err := a.closeDatabase(); err != nil {
 		fmt.Printf("关闭数据库失败: %v\n", err)
 	}
 }
 
-// preloadTasks 预加载任务数据到缓存
+// preloadTasks 预加载任务数据到缓存（合并其他进程的修改，保留本进程尚未保存的修改）
 func (a *App) preloadTasks() {
 	a.cacheMutex.Lock()
 	defer a.cacheMutex.Unlock()
 
-	tasks := a.loadTasksFromDisk()
-	a.tasksCache = tasks
+	if err := a.syncTasksWithDisk(false); err != nil {
+		fmt.Printf("加载任务失败: %v\n", err)
+	}
 	a.lastCacheTime = time.Now()
 }
 
@@ -180,27 +263,19 @@ func (a *App) getTasksPath() string {
 	return filepath.Join(dataDir, "tasks.json")
 }
 
-// loadTasksFromDisk 从磁盘加载任务
-func (a *App) loadTasksFromDisk() map[string]*Task {
-	tasks := make(map[string]*Task)
-
-	data, err := os.ReadFile(a.getTasksPath())
-	if err != nil {
-		return tasks
-	}
+// saveTasksCache 将缓存中的任务保存到磁盘
+func (a *App) saveTasksCache() error {
+	a.cacheMutex.Lock()
+	err := a.saveTasksToDisk()
+	a.cacheMutex.Unlock()
 
-	json.Unmarshal(data, &tasks)
-	return tasks
+	return err
 }
 
-// saveTasksToDisk 保存任务到磁盘
-func (a *App) saveTasksToDisk(tasks map[string]*Task) error {
-	data, err := json.MarshalIndent(tasks, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(a.getTasksPath(), data, 0644)
+// saveTasksToDisk 保存任务到磁盘（调用方需持有cacheMutex写锁）
+// 任务文件由桌面程序、守护进程和命令行共用，写入前在文件锁内合并其他进程的修改
+func (a *App) saveTasksToDisk() error {
+	return a.syncTasksWithDisk(true)
 }
 
 // GetTasks 获取任务列表（带分页）
@@ -249,12 +324,21 @@ func (a *App) GetTaskCount() int {
 
 // SaveTask 保存任务
 func (a *App) SaveTask(name, url, method, headersText, data string, times, threads, delayMin, delayMax int, tags []string, cronExpr string, successCondition SuccessCondition) string {
+	if _, err := a.createTask(name, url, method, headersText, data, times, threads, delayMin, delayMax, tags, cronExpr, successCondition); err != nil {
+		return fmt.Sprintf("错误：%v", err)
+	}
+
+	return fmt.Sprintf("任务 '%s' 保存成功", name)
+}
+
+// createTask 创建任务并保存，返回新任务
+func (a *App) createTask(name, url, method, headersText, data string, times, threads, delayMin, delayMax int, tags []string, cronExpr string, successCondition SuccessCondition) (*Task, error) {
 	if name == "" || url == "" {
-		return "错误：任务名称和URL不能为空"
+		return nil, inputErrorf("任务名称和URL不能为空")
 	}
 
 	// 生成任务ID
-	taskID := fmt.Sprintf("task_%d", time.Now().UnixNano())
+	taskID := newTaskID()
 
 	// 解析headers文本
 	headers := a.parseHeadersText(headersText)
@@ -280,31 +364,62 @@ func (a *App) SaveTask(name, url, method, headersText, data string, times, threa
 	}
 
 	// 更新缓存和磁盘
-	a.cacheMutex.Lock()
-	a.tasksCache[taskID] = task
-	tasks := make(map[string]*Task)
-	for k, v := range a.tasksCache {
-		tasks[k] = v
+	if err := a.addTasks([]*Task{task}); err != nil {
+		return nil, fmt.Errorf("保存失败：%v", err)
 	}
-	a.cacheMutex.Unlock()
 
-	if err := a.saveTasksToDisk(tasks); err != nil {
-		return fmt.Sprintf("保存失败：%v", err)
+	return task, nil
+}
+
+// lastTaskIDNano 最近一次生成任务ID使用的时间戳，保证批量导入时ID不重复
+var lastTaskIDNano int64
+
+// newTaskID 生成唯一的任务ID
+func newTaskID() string {
+	for {
+		last := atomic.LoadInt64(&lastTaskIDNano)
+		next := time.Now().UnixNano()
+		if next <= last {
+			next = last + 1
+		}
+		if atomic.CompareAndSwapInt64(&lastTaskIDNano, last, next) {
+			return fmt.Sprintf("task_%d", next)
+		}
 	}
+}
 
-	return fmt.Sprintf("任务 '%s' 保存成功", name)
+// addTasks 将新任务加入缓存并保存到磁盘（用于保存和导入）
+func (a *App) addTasks(newTasks []*Task) error {
+	a.cacheMutex.Lock()
+	for _, task := range newTasks {
+		a.tasksCache[task.ID] = task
+	}
+	err := a.saveTasksToDisk()
+	a.cacheMutex.Unlock()
+
+	return err
 }
 
 // UpdateTask 更新任务
 func (a *App) UpdateTask(taskID, name, url, method, headersText, data string, times, threads, delayMin, delayMax int, tags []string, cronExpr string, successCondition SuccessCondition) string {
+	if err := a.updateTask(taskID, name, url, method, headersText, data, times, threads, delayMin, delayMax, tags, cronExpr, successCondition); err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("任务 '%s' 更新成功", name)
+}
+
+// updateTask 更新任务，Cron表达式变化时重新注册定时调度
+func (a *App) updateTask(taskID, name, url, method, headersText, data string, times, threads, delayMin, delayMax int, tags []string, cronExpr string, successCondition SuccessCondition) error {
 	a.cacheMutex.Lock()
-	defer a.cacheMutex.Unlock()
 
 	task, exists := a.tasksCache[taskID]
 	if !exists {
-		return "错误：任务不存在"
+		a.cacheMutex.Unlock()
+		return errTaskNotFound
 	}
 
+	cronChanged := task.CronExpr != cronExpr
+
 	// 更新任务信息
 	task.Name = name
 	task.URL = url
@@ -322,90 +437,189 @@ func (a *App) UpdateTask(taskID, name, url, method, headersText, data string, ti
 	task.UpdatedAt = time.Now().Unix()
 
 	// 保存到磁盘
-	tasks := make(map[string]*Task)
-	for k, v := range a.tasksCache {
-		tasks[k] = v
+	if err := a.saveTasksToDisk(); err != nil {
+		a.cacheMutex.Unlock()
+		return fmt.Errorf("更新失败：%v", err)
 	}
+	a.cacheMutex.Unlock()
 
-	if err := a.saveTasksToDisk(tasks); err != nil {
-		return fmt.Sprintf("更新失败：%v", err)
+	// 定时调度在触发时读取最新的任务定义，只有Cron表达式变化时需要重新注册
+	if cronChanged {
+		if err := a.rescheduleTask(taskID); err != nil {
+			return fmt.Errorf("任务 '%s' 已更新，但重新注册定时调度失败：%v", name, err)
+		}
 	}
 
-	return fmt.Sprintf("任务 '%s' 更新成功", name)
+	return nil
 }
 
-// DeleteTask 删除任务
+// DeleteTask 删除任务，同时移除它的定时调度
 func (a *App) DeleteTask(taskID string) string {
+	name, err := a.deleteTask(taskID)
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("任务 '%s' 删除成功", name)
+}
+
+// deleteTask 删除任务及其定时调度和历史记录，返回任务名称
+func (a *App) deleteTask(taskID string) (string, error) {
 	a.cacheMutex.Lock()
-	defer a.cacheMutex.Unlock()
 
 	task, exists := a.tasksCache[taskID]
 	if !exists {
-		return "错误：任务不存在"
+		a.cacheMutex.Unlock()
+		return "", errTaskNotFound
 	}
 
 	name := task.Name
 	delete(a.tasksCache, taskID)
 
 	// 保存到磁盘
-	tasks := make(map[string]*Task)
-	for k, v := range a.tasksCache {
-		tasks[k] = v
+	err := a.saveTasksToDisk()
+	a.cacheMutex.Unlock()
+	if err != nil {
+		return "", fmt.Errorf("删除失败：%v", err)
 	}
 
-	if err := a.saveTasksToDisk(tasks); err != nil {
-		return fmt.Sprintf("删除失败：%v", err)
+	a.removeSchedule(taskID)
+	a.metrics.removeTask(taskID)
+
+	// 删除执行历史和定时触发记录
+	if err := a.dbDeleteRunRecords(taskID); err != nil {
+		fmt.Printf("删除执行历史失败: %v\n", err)
+	}
+	if err := a.dbDeleteScheduleFires(taskID); err != nil {
+		fmt.Printf("删除定时触发记录失败: %v\n", err)
 	}
 
-	return fmt.Sprintf("任务 '%s' 删除成功", name)
+	return name, nil
+}
+
+// 执行触发方式
+const (
+	TriggerManual   = "manual"   // 界面手动执行
+	TriggerSchedule = "schedule" // 定时调度
+	TriggerAPI      = "api"      // 本地REST接口
+	TriggerCLI      = "cli"      // 命令行
+	TriggerChained  = "chained"  // 由其他任务触发
+	TriggerCatchUp  = "catchup"  // 补偿错过的定时执行
+)
+
+// triggerTexts 触发方式在日志中的描述
+var triggerTexts = map[string]string{
+	TriggerManual:   "",
+	TriggerSchedule: "定时",
+	TriggerAPI:      "接口",
+	TriggerCLI:      "命令行",
+	TriggerChained:  "链式",
+	TriggerCatchUp:  "补偿",
+}
+
+// RunResult 一次执行的结果
+type RunResult struct {
+	TaskID       string `json:"taskId"`
+	TaskName     string `json:"taskName"`
+	TaskLogID    string `json:"taskLogId"`
+	Trigger      string `json:"trigger"`
+	Status       string `json:"status"` // success, partial, failed, stopped
+	Total        int    `json:"total"`
+	SuccessCount int    `json:"successCount"`
+	FailedCount  int    `json:"failedCount"`
+	Variants     int    `json:"variants"` // 分隔符产生的变体数
+	StartTime    int64  `json:"startTime"`
+	Duration     int64  `json:"duration"`
+	Summary      string `json:"summary"`
+}
+
+// lastRunResult 生成任务列表中显示的最后执行结果
+func (r RunResult) lastRunResult() string {
+	if r.Status == "stopped" {
+		return fmt.Sprintf("已停止，成功%d次，失败%d次", r.SuccessCount, r.FailedCount)
+	}
+	if r.SuccessCount == 0 {
+		return fmt.Sprintf("全部失败，共%d次请求", r.Total)
+	}
+	return fmt.Sprintf("成功%d次，失败%d次", r.SuccessCount, r.FailedCount)
 }
 
 // ExecuteTask 执行任务
 func (a *App) ExecuteTask(taskID string) string {
+	task, err := a.startTask(taskID, TriggerManual)
+	if err != nil {
+		return err.Error()
+	}
+
+	return fmt.Sprintf("任务 '%s' 开始执行", task.Name)
+}
+
+// startTask 在后台执行任务（任务正在运行时返回错误）
+func (a *App) startTask(taskID, trigger string) (*Task, error) {
 	a.cacheMutex.RLock()
 	task, exists := a.tasksCache[taskID]
 	a.cacheMutex.RUnlock()
 
 	if !exists {
-		return "错误：任务不存在"
+		return nil, errTaskNotFound
 	}
 
-	// 检查是否已在运行
-	a.taskMutex.RLock()
+	// 检查是否已在运行，并在同一次加锁中登记运行，避免并发启动
+	a.taskMutex.Lock()
 	if _, running := a.runningTasks[taskID]; running {
-		a.taskMutex.RUnlock()
-		return "错误：任务正在运行中"
+		a.taskMutex.Unlock()
+		return nil, errTaskRunning
 	}
-	a.taskMutex.RUnlock()
+	progress := a.addRunLocked(taskID)
+	a.taskMutex.Unlock()
 
 	// 启动任务
-	go a.runTask(task)
+	go a.runTask(task, trigger, progress)
 
-	return fmt.Sprintf("任务 '%s' 开始执行", task.Name)
+	return task, nil
 }
 
-// runTask 运行任务的核心逻辑
-func (a *App) runTask(task *Task) {
-	// 创建支持分隔符的任务副本列表
-	tasksWithVars := a.createTasksWithSeparatedVariables(task)
-	totalTasks := len(tasksWithVars)
-	totalTimes := task.Times * totalTasks
-	// 设置运行状态
+// addRunLocked 登记任务的一次运行（调用方需持有taskMutex写锁，停止任务时通过cancel中断执行）
+func (a *App) addRunLocked(taskID string) *TaskProgress {
+	ctx, cancel := context.WithCancel(context.Background())
 	progress := &TaskProgress{
 		Current:   0,
-		Total:     totalTimes,
 		StartTime: time.Now().Unix(),
 		IsRunning: true,
+		ctx:       ctx,
+		cancel:    cancel,
+		done:      make(chan struct{}),
 	}
+	a.runningTasks[taskID] = append(a.runningTasks[taskID], progress)
+	return progress
+}
 
+// addRun 登记任务的一次运行（不检查任务是否正在运行）
+func (a *App) addRun(taskID string) *TaskProgress {
 	a.taskMutex.Lock()
-	a.runningTasks[task.ID] = progress
-	a.taskMutex.Unlock()
+	defer a.taskMutex.Unlock()
+	return a.addRunLocked(taskID)
+}
+
+// runTask 运行任务的核心逻辑，所有触发方式共用（progress 由调用方通过 addRunLocked 登记）
+func (a *App) runTask(task *Task, trigger string, progress *TaskProgress) RunResult {
+	ctx := progress.ctx
+	defer progress.cancel()
 
 	// 更新任务状态
-	a.cacheMutex.Lock()
-	a.tasksCache[task.ID].IsRunning = true
-	a.cacheMutex.Unlock()
+	a.setTaskRunning(task.ID, true)
+	a.updateLastRunInfo(task.ID, "running", "执行中...")
+
+	// 创建支持分隔符的任务副本列表
+	tasksWithVars := a.createTasksWithSeparatedVariables(task)
+	totalTasks := len(tasksWithVars)
+	totalTimes := task.Times * totalTasks
+
+	a.taskMutex.Lock()
+	progress.Total = totalTimes
+	a.taskMutex.Unlock()
+
+	a.emitEvent(EventTaskStarted, TaskStartedEvent{TaskID: task.ID, TaskName: task.Name, Trigger: trigger, Total: totalTimes, StartTime: progress.StartTime})
+	progressEvents := &progressEmitter{app: a, taskID: task.ID, total: totalTimes}
 
 	// 创建详细日志收集器
 	var detailedLogs []DetailedLogEntry
@@ -415,9 +629,15 @@ func (a *App) runTask(task *Task) {
 	jobs := make(chan *Task, totalTimes)
 	results := make(chan bool, totalTimes)
 
-	// 启动工作协程
+	// 启动工作协程（所有变体共用任务的连接设置）
+	client := a.newHTTPClient(task)
+	var workers sync.WaitGroup
 	for w := 0; w < task.Threads; w++ {
-		go a.workerWithDetailedLogForTask(jobs, results, detailLogsChan)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			a.workerWithDetailedLogForTask(ctx, client, jobs, results, detailLogsChan, progressEvents)
+		}()
 	}
 
 	// 发送任务（每个任务副本执行指定次数）
@@ -428,15 +648,19 @@ func (a *App) runTask(task *Task) {
 	}
 	close(jobs)
 
-	// 等待完成并收集详细日志
+	// 等待完成（或被停止）并收集详细日志
 	completed := 0
 	successCount := 0
-	for completed < totalTimes {
-		success := <-results
-		if success {
-			successCount++
+	for completed < totalTimes && ctx.Err() == nil {
+		select {
+		case success := <-results:
+			if success {
+				successCount++
+			}
+			completed++
+		case <-ctx.Done():
+			continue
 		}
-		completed++
 
 		// 收集详细日志
 		select {
@@ -447,182 +671,182 @@ func (a *App) runTask(task *Task) {
 
 		// 更新进度
 		a.taskMutex.Lock()
-		if prog, exists := a.runningTasks[task.ID]; exists {
-			prog.Current = completed
-		}
+		progress.Current = completed
 		a.taskMutex.Unlock()
+		progressEvents.update(completed, successCount)
 	}
 
-	// 收集剩余的详细日志
+	// 等待工作协程退出后收集剩余的结果和详细日志
+	workers.Wait()
+	progressEvents.flushRequests()
+	close(results)
+	for success := range results {
+		if success {
+			successCount++
+		}
+		completed++
+	}
 	close(detailLogsChan)
 	for detailLog := range detailLogsChan {
 		detailedLogs = append(detailedLogs, detailLog)
 	}
 
 	// 记录任务完成（只记录关键结果）
-	duration := time.Now().Unix() - progress.StartTime
-	status := "success"
-	if successCount == 0 {
-		status = "failed"
+	result := RunResult{
+		TaskID:       task.ID,
+		TaskName:     task.Name,
+		Trigger:      trigger,
+		Status:       "success",
+		Total:        totalTimes,
+		SuccessCount: successCount,
+		FailedCount:  completed - successCount,
+		Variants:     totalTasks,
+		StartTime:    progress.StartTime,
+		Duration:     time.Now().Unix() - progress.StartTime,
+	}
+	if completed < totalTimes {
+		result.Status = "stopped"
+	} else if successCount == 0 {
+		result.Status = "failed"
 	} else if successCount < totalTimes {
-		status = "partial"
+		result.Status = "partial"
 	}
 
+	triggerText := triggerTexts[trigger]
 	var message string
-	if totalTasks > 1 {
-		message = fmt.Sprintf("任务 '%s' 执行完成，耗时: %d秒，成功: %d/%d（分隔符产生%d个变体，每个执行%d次）",
-			task.Name, duration, successCount, totalTimes, totalTasks, task.Times)
+	if result.Status == "stopped" {
+		message = fmt.Sprintf("任务 '%s' %s执行已停止，耗时: %d秒，已完成: %d/%d，成功: %d", task.Name, triggerText, result.Duration, completed, totalTimes, successCount)
 	} else {
-		message = fmt.Sprintf("任务 '%s' 执行完成，耗时: %d秒，成功: %d/%d", task.Name, duration, successCount, totalTimes)
+		message = fmt.Sprintf("任务 '%s' %s执行完成，耗时: %d秒，成功: %d/%d", task.Name, triggerText, result.Duration, successCount, totalTimes)
 	}
-	logID := a.writeTaskLog(task.ID, message, "execution", status)
+	if totalTasks > 1 {
+		message += fmt.Sprintf("（分隔符产生%d个变体，每个执行%d次）", totalTasks, task.Times)
+	}
+	result.TaskLogID = a.writeTaskLog(task.ID, message, "execution", result.Status)
 
 	// 保存详细日志
-	summary := fmt.Sprintf("执行完成，成功率: %.1f%%", float64(successCount)/float64(totalTimes)*100)
-	a.writeExecutionLog(logID, detailedLogs, summary, totalTimes, successCount, totalTimes-successCount, duration)
-
-	// 清理
-	a.taskMutex.Lock()
-	delete(a.runningTasks, task.ID)
-	a.taskMutex.Unlock()
-
-	a.cacheMutex.Lock()
-	a.tasksCache[task.ID].IsRunning = false
-	a.cacheMutex.Unlock()
-}
-
-// runTaskWithResult 运行任务并返回结果（用于定时任务）
-func (a *App) runTaskWithResult(task *Task) (bool, int, string) {
-	// 创建替换了环境变量的任务副本
-	taskWithVars := a.createTaskWithVariables(task)
-	// 设置运行状态
-	progress := &TaskProgress{
-		Current:   0,
-		Total:     task.Times,
-		StartTime: time.Now().Unix(),
-		IsRunning: true,
+	successRate := 0.0
+	if completed > 0 {
+		successRate = float64(successCount) / float64(completed) * 100
+	}
+	result.Summary = fmt.Sprintf("%s执行完成，成功率: %.1f%%", triggerText, successRate)
+	if result.Status == "stopped" {
+		result.Summary = fmt.Sprintf("%s执行已停止，完成%d/%d次请求，成功率: %.1f%%", triggerText, completed, totalTimes, successRate)
+	}
+	a.writeExecutionLog(result.TaskLogID, detailedLogs, result.Summary, completed, successCount, result.FailedCount, result.Duration)
+	a.recordRunHistory(task.ID, result.TaskLogID, result.Status, result.StartTime, result.Duration)
+	a.metrics.observeRun(task.ID, result.Status, trigger, result.Duration)
+	if result.Status != "stopped" {
+		a.notifyRunFinished(task, result.TaskLogID, result.Status, trigger)
 	}
+	a.emitEvent(EventTaskFinished, TaskFinishedEvent{TaskID: task.ID, TaskLogID: result.TaskLogID, Trigger: trigger, Status: result.Status,
+		Total: totalTimes, SuccessCount: successCount, FailedCount: result.FailedCount, Duration: result.Duration, Summary: result.Summary})
 
+	// 清理，任务的所有运行都结束后再启动排队的定时执行
 	a.taskMutex.Lock()
-	a.runningTasks[task.ID] = progress
+	remaining := a.removeRunLocked(task.ID, progress)
+	fireID, queued := a.queuedRuns[task.ID]
+	queued = queued && remaining == 0
+	var next *TaskProgress
+	if queued {
+		// 在同一次加锁中登记排队的执行，避免其他触发在此期间启动
+		delete(a.queuedRuns, task.ID)
+		next = a.addRunLocked(task.ID)
+	}
 	a.taskMutex.Unlock()
 
-	// 更新任务状态
-	a.cacheMutex.Lock()
-	a.tasksCache[task.ID].IsRunning = true
-	a.cacheMutex.Unlock()
-
-	// 创建详细日志收集器
-	var detailedLogs []DetailedLogEntry
-	detailLogsChan := make(chan DetailedLogEntry, task.Times)
-
-	// 创建工作通道
-	jobs := make(chan int, task.Times)
-	results := make(chan bool, task.Times)
-
-	// 启动工作协程
-	for w := 0; w < task.Threads; w++ {
-		go a.workerWithDetailedLog(taskWithVars, jobs, results, detailLogsChan)
+	if remaining == 0 && !queued {
+		a.setTaskRunning(task.ID, false)
 	}
+	a.updateLastRunInfo(task.ID, result.Status, result.lastRunResult())
+	// 执行结果已提交保存，等待本次运行的协程（如守护进程退出）此时可以继续
+	close(progress.done)
 
-	// 发送任务
-	for i := 0; i < task.Times; i++ {
-		jobs <- i + 1
+	if queued {
+		go a.runQueuedSchedule(task.ID, fireID, next)
 	}
-	close(jobs)
 
-	// 等待完成并收集详细日志
-	completed := 0
-	successCount := 0
-	for completed < task.Times {
-		success := <-results
-		if success {
-			successCount++
-		}
-		completed++
-
-		// 收集详细日志
-		select {
-		case detailLog := <-detailLogsChan:
-			detailedLogs = append(detailedLogs, detailLog)
-		default:
-		}
+	return result
+}
 
-		// 更新进度
-		a.taskMutex.Lock()
-		if prog, exists := a.runningTasks[task.ID]; exists {
-			prog.Current = completed
+// removeRunLocked 移除已结束的运行，返回任务剩余的运行数（调用方需持有taskMutex）
+func (a *App) removeRunLocked(taskID string, progress *TaskProgress) int {
+	runs := a.runningTasks[taskID]
+	for i, run := range runs {
+		if run == progress {
+			runs = append(runs[:i:i], runs[i+1:]...)
+			break
 		}
-		a.taskMutex.Unlock()
-	}
-
-	// 收集剩余的详细日志
-	close(detailLogsChan)
-	for detailLog := range detailLogsChan {
-		detailedLogs = append(detailedLogs, detailLog)
 	}
 
-	// 记录任务完成（只记录关键结果）
-	duration := time.Now().Unix() - progress.StartTime
-	status := "success"
-	if successCount == 0 {
-		status = "failed"
-	} else if successCount < task.Times {
-		status = "partial"
+	if len(runs) == 0 {
+		delete(a.runningTasks, taskID)
+	} else {
+		a.runningTasks[taskID] = runs
 	}
+	return len(runs)
+}
 
-	message := fmt.Sprintf("定时任务 '%s' 执行完成，耗时: %d秒，成功: %d/%d", task.Name, duration, successCount, task.Times)
-	logID := a.writeTaskLog(task.ID, message, "execution", status)
-
-	// 保存详细日志
-	summary := fmt.Sprintf("定时执行完成，成功率: %.1f%%", float64(successCount)/float64(task.Times)*100)
-	a.writeExecutionLog(logID, detailedLogs, summary, task.Times, successCount, task.Times-successCount, duration)
-
-	// 清理
+// abandonRun 撤销已登记但不再执行的运行
+func (a *App) abandonRun(taskID string, progress *TaskProgress) {
 	a.taskMutex.Lock()
-	delete(a.runningTasks, task.ID)
+	remaining := a.removeRunLocked(taskID, progress)
 	a.taskMutex.Unlock()
 
+	if remaining == 0 {
+		a.setTaskRunning(taskID, false)
+	}
+	progress.cancel()
+	close(progress.done)
+}
+
+// setTaskRunning 更新缓存中任务的运行状态（执行期间任务可能已被删除）
+func (a *App) setTaskRunning(taskID string, running bool) {
 	a.cacheMutex.Lock()
-	a.tasksCache[task.ID].IsRunning = false
-	a.cacheMutex.Unlock()
+	defer a.cacheMutex.Unlock()
 
-	// 返回结果
-	if successCount > 0 {
-		return true, successCount, fmt.Sprintf("成功%d次，失败%d次", successCount, task.Times-successCount)
-	} else {
-		return false, 0, fmt.Sprintf("全部失败，共%d次请求", task.Times)
+	if task, exists := a.tasksCache[taskID]; exists {
+		task.IsRunning = running
 	}
 }
 
-// worker 工作协程
-func (a *App) worker(task *Task, jobs <-chan int, results chan<- bool) {
+// newHTTPClient 根据任务的连接设置（跳过证书校验、代理）创建HTTP客户端
+func (a *App) newHTTPClient(task *Task) *http.Client {
 	client := &http.Client{
 		Timeout: 30 * time.Second,
 	}
 
-	for range jobs {
-		success := a.makeRequest(client, task)
-		results <- success
+	if !task.Insecure && task.Proxy == "" {
+		return client
+	}
 
-		// 随机延迟
-		if task.DelayMax > task.DelayMin {
-			delay := task.DelayMin + rand.Intn(task.DelayMax-task.DelayMin)
-			time.Sleep(time.Duration(delay) * time.Millisecond)
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if task.Insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	if task.Proxy != "" {
+		proxy := task.Proxy
+		if !strings.Contains(proxy, "://") {
+			proxy = "http://" + proxy
+		}
+		if proxyURL, err := url.Parse(proxy); err == nil {
+			transport.Proxy = http.ProxyURL(proxyURL)
+		} else {
+			fmt.Printf("代理地址无效，忽略代理设置: %v\n", err)
 		}
 	}
+	client.Transport = transport
+
+	return client
 }
 
-// workerWithDetailedLog 带详细日志的工作协程
-func (a *App) workerWithDetailedLog(task *Task, jobs <-chan int, results chan<- bool, detailLogs chan<- DetailedLogEntry) {
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
+// worker 工作协程
+func (a *App) worker(task *Task, jobs <-chan int, results chan<- bool) {
+	client := a.newHTTPClient(task)
 
 	for range jobs {
-		success, detailLog := a.makeRequestWithDetailedLog(client, task)
+		success := a.makeRequest(client, task)
 		results <- success
-		detailLogs <- detailLog
 
 		// 随机延迟
 		if task.DelayMax > task.DelayMin {
@@ -632,21 +856,29 @@ func (a *App) workerWithDetailedLog(task *Task, jobs <-chan int, results chan<-
 	}
 }
 
-// workerWithDetailedLogForTask 支持分隔符的带详细日志工作协程
-func (a *App) workerWithDetailedLogForTask(jobs <-chan *Task, results chan<- bool, detailLogs chan<- DetailedLogEntry) {
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-
+// workerWithDetailedLogForTask 支持分隔符的带详细日志工作协程，停止执行后丢弃剩余请求
+func (a *App) workerWithDetailedLogForTask(ctx context.Context, client *http.Client, jobs <-chan *Task, results chan<- bool, detailLogs chan<- DetailedLogEntry, progressEvents *progressEmitter) {
 	for task := range jobs {
-		success, detailLog := a.makeRequestWithDetailedLog(client, task)
+		if ctx.Err() != nil {
+			return
+		}
+
+		success, detailLog := a.makeRequestWithDetailedLog(ctx, client, task)
+		if ctx.Err() != nil {
+			return // 停止时被中断的请求不计入结果
+		}
+		progressEvents.addRequest(detailLog)
 		results <- success
 		detailLogs <- detailLog
 
 		// 随机延迟
 		if task.DelayMax > task.DelayMin {
 			delay := task.DelayMin + rand.Intn(task.DelayMax-task.DelayMin)
-			time.Sleep(time.Duration(delay) * time.Millisecond)
+			select {
+			case <-time.After(time.Duration(delay) * time.Millisecond):
+			case <-ctx.Done():
+				return
+			}
 		}
 	}
 }
@@ -698,15 +930,24 @@ func (a *App) makeRequest(client *http.Client, task *Task) bool {
 	return a.evaluateSuccessCondition(task, resp, responseBody)
 }
 
-// makeRequestWithDetailedLog 发送HTTP请求并记录详细日志
-func (a *App) makeRequestWithDetailedLog(client *http.Client, task *Task) (bool, DetailedLogEntry) {
+// makeRequestWithDetailedLog 发送请求并记录指标
+func (a *App) makeRequestWithDetailedLog(ctx context.Context, client *http.Client, task *Task) (bool, DetailedLogEntry) {
+	success, detailLog := a.sendRequestWithDetailedLog(ctx, client, task)
+	a.metrics.observeRequest(task.ID, detailLog)
+	return success, detailLog
+}
+
+// sendRequestWithDetailedLog 发送HTTP请求并记录详细日志
+func (a *App) sendRequestWithDetailedLog(ctx context.Context, client *http.Client, task *Task) (bool, DetailedLogEntry) {
 	startTime := time.Now()
 	var body io.Reader
 	if task.Data != "" {
 		body = strings.NewReader(task.Data)
 	}
 
-	req, err := http.NewRequest(task.Method, task.URL, body)
+	captureConfig := a.getCaptureConfig(task)
+
+	req, err := http.NewRequestWithContext(ctx, task.Method, task.URL, body)
 	if err != nil {
 		return false, a.addDetailedLogEntryWithError(task.ID, task.URL, task.Method, 0, 0, "", err.Error(), false, "network", fmt.Sprintf("创建HTTP请求失败: %v", err), nil)
 	}
@@ -732,16 +973,20 @@ func (a *App) makeRequestWithDetailedLog(client *http.Client, task *Task) (bool,
 
 	if err != nil {
 		detailedError := fmt.Sprintf("网络请求失败: %v", err)
-		return false, a.addDetailedLogEntryWithError(task.ID, task.URL, task.Method, 0, responseTime, "", err.Error(), false, "network", detailedError, nil)
+		detailLog := a.addDetailedLogEntryWithError(task.ID, task.URL, task.Method, 0, responseTime, "", err.Error(), false, "network", detailedError, nil)
+		captureExchange(&detailLog, req, task.Data, nil, captureConfig)
+		return false, detailLog
 	}
 	defer resp.Body.Close()
 
-	// 读取响应内容（限制大小）
-	responseBody, err := io.ReadAll(io.LimitReader(resp.Body, 1024*10)) // 限制10KB
+	// 读取响应内容（限制大小，保存到日志的部分由保留策略决定）
+	responseBody, err := io.ReadAll(io.LimitReader(resp.Body, maxEvaluatedResponseBytes))
 	responseStr := ""
 	if err != nil {
 		detailedError := fmt.Sprintf("读取响应内容失败: %v", err)
-		return false, a.addDetailedLogEntryWithError(task.ID, task.URL, task.Method, resp.StatusCode, responseTime, "", err.Error(), false, "parsing", detailedError, nil)
+		detailLog := a.addDetailedLogEntryWithError(task.ID, task.URL, task.Method, resp.StatusCode, responseTime, "", err.Error(), false, "parsing", detailedError, nil)
+		captureExchange(&detailLog, req, task.Data, resp, captureConfig)
+		return false, detailLog
 	}
 	responseStr = string(responseBody)
 
@@ -763,17 +1008,19 @@ func (a *App) makeRequestWithDetailedLog(client *http.Client, task *Task) (bool,
 		}
 	}
 
-	detailLog := a.addDetailedLogEntryWithError(task.ID, task.URL, task.Method, resp.StatusCode, responseTime, responseStr, errorMsg, success, errorType, detailedError, successConditionDetails)
+	storedResponse := retainResponseBody(responseStr, success, a.getRetentionPolicy(task.ID))
+	detailLog := a.addDetailedLogEntryWithError(task.ID, task.URL, task.Method, resp.StatusCode, responseTime, storedResponse, errorMsg, success, errorType, detailedError, successConditionDetails)
+	captureExchange(&detailLog, req, task.Data, resp, captureConfig)
 	return success, detailLog
 }
 
-// GetTaskProgress 获取任务进度
+// GetTaskProgress 获取任务进度（同时有多次运行时返回最近开始的一次）
 func (a *App) GetTaskProgress(taskID string) *TaskProgress {
 	a.taskMutex.RLock()
 	defer a.taskMutex.RUnlock()
 
-	if progress, exists := a.runningTasks[taskID]; exists {
-		return progress
+	if runs := a.runningTasks[taskID]; len(runs) > 0 {
+		return runs[len(runs)-1]
 	}
 
 	return &TaskProgress{
@@ -784,24 +1031,35 @@ func (a *App) GetTaskProgress(taskID string) *TaskProgress {
 	}
 }
 
-// StopTask 停止任务
+// StopTask 停止任务的所有运行，并取消排队的定时执行
 func (a *App) StopTask(taskID string) string {
-	a.taskMutex.Lock()
-	defer a.taskMutex.Unlock()
-
-	if _, exists := a.runningTasks[taskID]; !exists {
-		return "错误：任务未在运行"
+	if err := a.stopTask(taskID); err != nil {
+		return err.Error()
 	}
+	return "任务已停止"
+}
 
-	delete(a.runningTasks, taskID)
+// stopTask 停止任务的所有运行和排队的定时执行
+func (a *App) stopTask(taskID string) error {
+	a.taskMutex.Lock()
+	runs, exists := a.runningTasks[taskID]
+	if !exists {
+		a.taskMutex.Unlock()
+		return errTaskNotRunning
+	}
 
-	a.cacheMutex.Lock()
-	if task, exists := a.tasksCache[taskID]; exists {
-		task.IsRunning = false
+	// 执行协程收到停止信号后记录结果并清理运行状态
+	for _, run := range runs {
+		run.cancel()
 	}
-	a.cacheMutex.Unlock()
+	fireID, queued := a.queuedRuns[taskID]
+	delete(a.queuedRuns, taskID)
+	a.taskMutex.Unlock()
 
-	return "任务已停止"
+	if queued {
+		a.finishScheduleFire(fireID, "", "stopped")
+	}
+	return nil
 }
 
 // GetTaskLogs 获取任务执行日志（兼容性方法）
@@ -886,6 +1144,17 @@ func (a *App) GetExecutionLog(taskLogID string) *ExecutionLog {
 
 // ClearTaskLogs 清空任务日志
 func (a *App) ClearTaskLogs(taskID string) string {
+	if err := a.clearTaskLogs(taskID); err != nil {
+		return err.Error()
+	}
+	if taskID == "all" {
+		return "所有任务日志已清空"
+	}
+	return fmt.Sprintf("任务 '%s' 的日志已清空", taskID)
+}
+
+// clearTaskLogs 清空任务日志及执行历史和定时触发记录（taskID 为 all 时清空所有任务）
+func (a *App) clearTaskLogs(taskID string) error {
 	a.logMutex.Lock()
 	defer a.logMutex.Unlock()
 
@@ -893,16 +1162,22 @@ func (a *App) ClearTaskLogs(taskID string) string {
 		// 清空所有任务的日志
 		a.taskLogs = make(map[string][]TaskLogEntry)
 		a.executionLogs = make(map[string]ExecutionLog)
+		if err := a.dbDeleteRunRecords("all"); err != nil {
+			fmt.Printf("删除执行历史失败: %v\n", err)
+		}
+		if err := a.dbDeleteScheduleFires("all"); err != nil {
+			fmt.Printf("删除定时触发记录失败: %v\n", err)
+		}
 
 		// 保存清空后的日志到磁盘
-		go a.saveTaskLogs()
-		go a.saveExecutionLogs()
+		a.saveInBackground(a.saveTaskLogs)
+		a.saveInBackground(a.saveExecutionLogs)
 
-		return "所有任务日志已清空"
+		return nil
 	} else {
 		// 清空指定任务的日志
 		if _, exists := a.taskLogs[taskID]; !exists {
-			return "任务日志不存在"
+			return errLogsNotFound
 		}
 
 		// 获取要删除的执行日志ID列表
@@ -922,12 +1197,18 @@ func (a *App) ClearTaskLogs(taskID string) string {
 		for _, logID := range executionLogIDs {
 			delete(a.executionLogs, logID)
 		}
+		if err := a.dbDeleteRunRecords(taskID); err != nil {
+			fmt.Printf("删除执行历史失败: %v\n", err)
+		}
+		if err := a.dbDeleteScheduleFires(taskID); err != nil {
+			fmt.Printf("删除定时触发记录失败: %v\n", err)
+		}
 
 		// 保存更新后的日志到磁盘
-		go a.saveTaskLogs()
-		go a.saveExecutionLogs()
+		a.saveInBackground(a.saveTaskLogs)
+		a.saveInBackground(a.saveExecutionLogs)
 
-		return fmt.Sprintf("任务 '%s' 的日志已清空", taskID)
+		return nil
 	}
 }
 
@@ -947,6 +1228,8 @@ func (a *App) getTaskLogPath(taskID string) string {
 
 // writeTaskLog 写入任务级别日志（简洁版本）
 func (a *App) writeTaskLog(taskID, message, logType, status string) string {
+	maxRuns := a.getRetentionPolicy(taskID).MaxRuns
+
 	a.logMutex.Lock()
 	defer a.logMutex.Unlock()
 
@@ -973,9 +1256,9 @@ func (a *App) writeTaskLog(taskID, message, logType, status string) string {
 	}
 	a.taskLogs[taskID] = append(a.taskLogs[taskID], logEntry)
 
-	// 保持最近100条日志
-	if len(a.taskLogs[taskID]) > 100 {
-		a.taskLogs[taskID] = a.taskLogs[taskID][len(a.taskLogs[taskID])-100:]
+	// 按保留策略保持最近的日志（对应的详细日志由后台清理移除）
+	if maxRuns > 0 && len(a.taskLogs[taskID]) > maxRuns {
+		a.taskLogs[taskID] = a.taskLogs[taskID][len(a.taskLogs[taskID])-maxRuns:]
 	}
 
 	// 同时写入文件（兼容性）
@@ -992,7 +1275,7 @@ func (a *App) writeTaskLog(taskID, message, logType, status string) string {
 	file.WriteString(logFileEntry)
 
 	// 异步保存任务级别日志到JSON文件
-	go a.saveTaskLogs()
+	a.saveInBackground(a.saveTaskLogs)
 
 	return logID
 }
@@ -1010,12 +1293,13 @@ func (a *App) writeExecutionLog(taskLogID string, detailedLogs []DetailedLogEntr
 		SuccessCount:  successCount,
 		FailedCount:   failedCount,
 		Duration:      duration,
+		Stats:         computeRunStats(detailedLogs),
 	}
 
 	a.executionLogs[taskLogID] = executionLog
 
 	// 异步保存详细执行日志到JSON文件
-	go a.saveExecutionLogs()
+	a.saveInBackground(a.saveExecutionLogs)
 }
 
 // addDetailedLogEntry 添加详细日志条目（保持向后兼容）
@@ -1074,16 +1358,54 @@ func (a *App) parseHeadersText(headersText string) map[string]string {
 
 // ScheduleTask 添加定时任务
 func (a *App) ScheduleTask(taskID string) string {
+	task, err := a.enableSchedule(taskID)
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("任务 '%s' 已添加到定时调度", task.Name)
+}
+
+// enableSchedule 启用任务的定时调度
+func (a *App) enableSchedule(taskID string) (*Task, error) {
+	task, err := a.scheduleTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 从启用时开始计算错过的执行
+	a.recordScheduleFire(taskID, time.Now())
+	return task, nil
+}
+
+// scheduleTask 将任务添加到定时调度并保存调度状态
+func (a *App) scheduleTask(taskID string) (*Task, error) {
+	task, err := a.registerSchedule(taskID)
+	if err != nil {
+		if saveErr := a.updateScheduledTaskIDs(taskID, false); saveErr != nil {
+			fmt.Printf("保存定时任务状态失败: %v\n", saveErr)
+		}
+		return nil, err
+	}
+
+	// 保存定时任务状态到文件
+	if err := a.updateScheduledTaskIDs(taskID, true); err != nil {
+		fmt.Printf("保存定时任务状态失败: %v\n", err)
+	}
+	return task, nil
+}
+
+// registerSchedule 将任务注册到定时调度（不修改保存的调度状态，守护进程按状态文件注册时使用）
+func (a *App) registerSchedule(taskID string) (*Task, error) {
 	a.cacheMutex.RLock()
 	task, exists := a.tasksCache[taskID]
 	a.cacheMutex.RUnlock()
 
 	if !exists {
-		return "错误：任务不存在"
+		return nil, errTaskNotFound
 	}
 
 	if task.CronExpr == "" {
-		return "错误：任务没有设置定时表达式"
+		return nil, inputErrorf("错误：任务没有设置定时表达式")
 	}
 
 	a.cronMutex.Lock()
@@ -1094,66 +1416,102 @@ func (a *App) ScheduleTask(taskID string) string {
 		a.cronScheduler.Remove(entryID)
 	}
 
-	// 添加新的定时任务
-	entryID, err := a.cronScheduler.AddFunc(task.CronExpr, func() {
-		// 不记录定时任务触发日志，这属于系统级别日志
-
-		// 更新最后执行时间和状态
-		a.updateLastRunInfo(taskID, "running", "定时任务执行中...")
-
-		// 执行任务
-		go func() {
-			// 执行任务并获取结果
-			success, completed, errorMsg := a.runTaskWithResult(task)
-
-			// 更新执行结果
-			if success {
-				a.updateLastRunInfo(taskID, "success", fmt.Sprintf("执行成功，完成%d次请求", completed))
-			} else {
-				a.updateLastRunInfo(taskID, "failed", errorMsg)
-			}
-		}()
-	})
+	// 守护进程运行时由守护进程执行调度，本地只记录调度状态
+	if a.schedulingDelegated() {
+		a.cronJobs[taskID] = delegatedEntryID
+		return task, nil
+	}
 
+	// 添加新的定时任务（触发时按ID读取最新的任务定义）
+	entryID, err := a.addCronEntry(task)
 	if err != nil {
-		return fmt.Sprintf("添加定时任务失败: %v", err)
+		delete(a.cronJobs, taskID)
+		return nil, inputErrorf("添加定时任务失败: %v", err)
 	}
 
 	a.cronJobs[taskID] = entryID
 	// 不记录调度添加日志，这属于系统级别日志
 
-	// 保存定时任务状态到文件
-	go a.saveScheduledTasks()
-
-	return fmt.Sprintf("任务 '%s' 已添加到定时调度", task.Name)
+	return task, nil
 }
 
 // UnscheduleTask 移除定时任务
 func (a *App) UnscheduleTask(taskID string) string {
-	a.cronMutex.Lock()
-	defer a.cronMutex.Unlock()
+	if err := a.unscheduleTask(taskID); err != nil {
+		return err.Error()
+	}
+
+	a.cacheMutex.RLock()
+	task, exists := a.tasksCache[taskID]
+	a.cacheMutex.RUnlock()
+
+	if exists {
+		// 不记录调度移除日志，这属于系统级别日志
+		return fmt.Sprintf("任务 '%s' 已从定时调度中移除", task.Name)
+	}
+
+	return "定时任务已移除"
+}
+
+// unscheduleTask 移除任务的定时调度
+func (a *App) unscheduleTask(taskID string) error {
+	if !a.removeSchedule(taskID) {
+		return errTaskNotScheduled
+	}
+	return nil
+}
 
+// removeSchedule 移除任务的定时调度并保存调度状态，返回任务是否有调度
+func (a *App) removeSchedule(taskID string) bool {
+	a.cronMutex.Lock()
 	entryID, exists := a.cronJobs[taskID]
 	if !exists {
-		return "任务没有定时调度"
+		a.cronMutex.Unlock()
+		return false
 	}
 
 	a.cronScheduler.Remove(entryID)
 	delete(a.cronJobs, taskID)
+	a.cronMutex.Unlock()
 
 	// 保存定时任务状态到文件
-	go a.saveScheduledTasks()
+	if err := a.updateScheduledTaskIDs(taskID, false); err != nil {
+		fmt.Printf("保存定时任务状态失败: %v\n", err)
+	}
+	a.clearScheduleFire(taskID)
+
+	return true
+}
+
+// rescheduleTask Cron表达式变化后重新注册已调度的任务（表达式被清空时移除调度）
+func (a *App) rescheduleTask(taskID string) error {
+	a.cronMutex.RLock()
+	_, scheduled := a.cronJobs[taskID]
+	a.cronMutex.RUnlock()
+
+	if !scheduled {
+		return nil
+	}
 
 	a.cacheMutex.RLock()
-	task, exists := a.tasksCache[taskID]
+	cronExpr := ""
+	if task, exists := a.tasksCache[taskID]; exists {
+		cronExpr = task.CronExpr
+	}
 	a.cacheMutex.RUnlock()
 
-	if exists {
-		// 不记录调度移除日志，这属于系统级别日志
-		return fmt.Sprintf("任务 '%s' 已从定时调度中移除", task.Name)
+	if cronExpr == "" {
+		a.removeSchedule(taskID)
+		return nil
 	}
 
-	return "定时任务已移除"
+	if _, err := a.scheduleTask(taskID); err != nil {
+		return err
+	}
+
+	// 触发规则已变化，从现在开始计算错过的执行
+	a.recordScheduleFire(taskID, time.Now())
+	return nil
 }
 
 // GetScheduledTasks 获取所有定时任务
@@ -1193,6 +1551,10 @@ type DetailedLogEntry struct {
 	SuccessConditionDetails *SuccessConditionDetails `json:"successConditionDetails"` // 成功条件评估详情
 	ErrorType               string                   `json:"errorType"`               // 错误类型: network, parsing, condition, http
 	DetailedError           string                   `json:"detailedError"`           // 详细错误描述
+	RequestHeaders          map[string]string        `json:"requestHeaders"`          // 实际发送的请求头（启用采集时记录）
+	RequestBody             string                   `json:"requestBody"`             // 实际发送的请求体（启用采集时记录）
+	RequestBodySize         int                      `json:"requestBodySize"`         // 请求体大小（启用采集时记录）
+	ResponseHeaders         map[string]string        `json:"responseHeaders"`         // 响应头（启用采集时记录）
 }
 
 // ExecutionLog 执行日志（包含任务级别和详细日志）
@@ -1204,19 +1566,22 @@ type ExecutionLog struct {
 	SuccessCount  int                `json:"successCount"`  // 成功数
 	FailedCount   int                `json:"failedCount"`   // 失败数
 	Duration      int64              `json:"duration"`      // 执行时长(秒)
+	Stats         *RunStats          `json:"stats"`         // 延迟统计
 }
 
 // TaskScheduleInfo 任务调度信息
 type TaskScheduleInfo struct {
-	TaskID          string `json:"taskId"`
-	IsScheduled     bool   `json:"isScheduled"`
-	CronExpr        string `json:"cronExpr"`
-	NextRunTime     string `json:"nextRunTime"`
-	CronDescription string `json:"cronDescription"`
-	Status          string `json:"status"` // idle, scheduled, running, error
-	LastRunTime     string `json:"lastRunTime"`
-	LastRunStatus   string `json:"lastRunStatus"` // success, failed, running
-	LastRunResult   string `json:"lastRunResult"`
+	TaskID          string   `json:"taskId"`
+	IsScheduled     bool     `json:"isScheduled"`
+	CronExpr        string   `json:"cronExpr"`
+	NextRunTime     string   `json:"nextRunTime"`
+	CronDescription string   `json:"cronDescription"`
+	Status          string   `json:"status"` // idle, scheduled, running, error
+	LastRunTime     string   `json:"lastRunTime"`
+	LastRunStatus   string   `json:"lastRunStatus"` // success, failed, running
+	LastRunResult   string   `json:"lastRunResult"`
+	TimeZone        string   `json:"timeZone"`     // 调度时区（为空时使用本机时区）
+	UpcomingRuns    []string `json:"upcomingRuns"` // 接下来的执行时间（本机时间，不含随机延迟）
 }
 
 // GetTaskScheduleInfo 获取任务调度信息
@@ -1236,7 +1601,9 @@ func (a *App) GetTaskScheduleInfo(taskID string) TaskScheduleInfo {
 		return info
 	}
 
+	options := a.getScheduleOptions(task)
 	info.CronExpr = task.CronExpr
+	info.TimeZone = options.TimeZone
 	info.LastRunTime = task.LastRunTime
 	info.LastRunStatus = task.LastRunStatus
 	info.LastRunResult = task.LastRunResult
@@ -1256,27 +1623,30 @@ func (a *App) GetTaskScheduleInfo(taskID string) TaskScheduleInfo {
 		info.IsScheduled = true
 		info.Status = "scheduled"
 
-		// 计算下次执行时间
-		nextTime, err := a.getNextRunTime(task.CronExpr)
+		// 计算接下来的执行时间（跳过生效时间范围之外的触发）
+		nextTimes, err := a.getNextRunTimes(task.CronExpr, options, upcomingRunCount)
 		if err != nil {
 			info.Status = "error"
 			info.NextRunTime = "计算失败: " + err.Error()
+		} else if len(nextTimes) == 0 {
+			info.NextRunTime = "已超出生效日期"
 		} else {
-			info.NextRunTime = nextTime
+			info.NextRunTime = nextTimes[0]
+			info.UpcomingRuns = nextTimes
 		}
 
 		// 生成人性化描述
-		info.CronDescription = a.describeCronExpr(task.CronExpr)
+		info.CronDescription = a.describeCronExpr(task.CronExpr, options.TimeZone) + options.describeWindow()
 
-		// 验证entry是否还存在
+		// 验证entry是否还存在（由守护进程执行的调度不在本地注册）
 		entry := a.cronScheduler.Entry(entryID)
-		if entry.ID == 0 {
+		if entryID != delegatedEntryID && entry.ID == 0 {
 			info.Status = "error"
 			info.NextRunTime = "调度已失效"
 		}
 	} else if task.CronExpr != "" {
 		info.Status = "idle"
-		info.CronDescription = a.describeCronExpr(task.CronExpr)
+		info.CronDescription = a.describeCronExpr(task.CronExpr, options.TimeZone) + options.describeWindow()
 	}
 
 	return info
@@ -1296,40 +1666,117 @@ func (a *App) updateLastRunInfo(taskID, status, result string) {
 	task.LastRunStatus = status
 	task.LastRunResult = result
 
+	// 保存到磁盘，使桌面程序和守护进程能看到对方的执行结果
+	a.saveInBackground(a.saveTasksCache)
+
 	// 不记录状态更新日志，这属于系统级别日志
 }
 
-// getNextRunTime 计算下次执行时间
-func (a *App) getNextRunTime(cronExpr string) (string, error) {
+// parseCronSchedule 解析Cron表达式（支持5字段、带秒的6字段和@daily等描述符），按时区计算触发时间
+func parseCronSchedule(cronExpr, timeZone string) (cron.Schedule, error) {
+	location, err := loadScheduleLocation(timeZone)
+	if err != nil {
+		return nil, err
+	}
+
 	fields := strings.Fields(cronExpr)
 	var schedule cron.Schedule
-	var err error
 
-	if len(fields) == 6 {
+	if len(fields) == 6 || strings.HasPrefix(cronExpr, "@") {
 		// 6字段格式（秒 分 时 日 月 周）- 使用支持秒的解析器
-		parser := cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+		parser := cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
 		schedule, err = parser.Parse(cronExpr)
 	} else if len(fields) == 5 {
 		// 5字段格式（分 时 日 月 周）- 使用标准解析器
 		schedule, err = cron.ParseStandard(cronExpr)
 	} else {
-		return "", fmt.Errorf("Cron表达式格式错误：期望5字段或6字段，实际%d字段", len(fields))
+		return nil, fmt.Errorf("Cron表达式格式错误：期望5字段或6字段，实际%d字段", len(fields))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("解析Cron表达式失败: %v", err)
+	}
+
+	if spec, ok := schedule.(*cron.SpecSchedule); ok {
+		spec.Location = location
+	}
+	return schedule, nil
+}
+
+// getNextRunTimes 计算接下来的执行时间（按调度时区计算、本机时间显示，跳过生效时间范围之外的触发）
+func (a *App) getNextRunTimes(cronExpr string, options ScheduleOptions, count int) ([]string, error) {
+	runTimes, err := scheduleRunTimes(cronExpr, options, time.Now(), time.Time{}, count)
+	if err != nil {
+		return nil, err
 	}
 
+	var times []string
+	for _, runTime := range runTimes {
+		times = append(times, runTime.Local().Format("2006-01-02 15:04:05"))
+	}
+	return times, nil
+}
+
+// scheduleRunTimes 计算 (from, until] 内最多 count 个生效的执行时间（until 为零值表示不限，跳过生效时间范围之外的触发）
+func scheduleRunTimes(cronExpr string, options ScheduleOptions, from, until time.Time, count int) ([]time.Time, error) {
+	var times []time.Time
+	_, err := walkScheduleRunTimes(cronExpr, options, from, until, count+maxScheduleLookahead, func(runTime time.Time) bool {
+		times = append(times, runTime)
+		return len(times) < count
+	})
+	return times, err
+}
+
+// walkScheduleRunTimes 依次对 (from, until] 内生效的执行时间调用 fn（until 为零值表示不限），fn 返回 false 时停止
+// 最多检查 limit 次触发，返回是否已检查完全部触发（fn 要求停止时返回 false）
+func walkScheduleRunTimes(cronExpr string, options ScheduleOptions, from, until time.Time, limit int, fn func(time.Time) bool) (bool, error) {
+	schedule, err := parseCronSchedule(cronExpr, options.TimeZone)
 	if err != nil {
-		return "", fmt.Errorf("解析Cron表达式失败: %v", err)
+		return false, err
 	}
+	location, _ := loadScheduleLocation(options.TimeZone)
+
+	current := from.In(location)
+	for i := 0; i < limit; i++ {
+		next := schedule.Next(current)
+		if next.IsZero() || (!until.IsZero() && next.After(until)) {
+			return true, nil
+		}
 
-	nextTime := schedule.Next(time.Now())
-	return nextTime.Format("2006-01-02 15:04:05"), nil
+		// 不在生效时间范围内时跳到下一个生效时间之前继续计算
+		active, ok := options.nextActiveTime(next, location)
+		if !ok {
+			return true, nil
+		}
+		if !active.Equal(next) {
+			current = active.Add(-time.Second)
+			continue
+		}
+
+		if !fn(next) {
+			return false, nil
+		}
+		current = next
+	}
+	return false, nil
 }
 
-// describeCronExpr 生成Cron表达式的人性化描述
-func (a *App) describeCronExpr(cronExpr string) string {
+// describeCronExpr 生成Cron表达式的人性化描述（设置了时区时附带时区）
+func (a *App) describeCronExpr(cronExpr, timeZone string) string {
 	if cronExpr == "" {
 		return ""
 	}
 
+	description := describeCronFields(cronExpr)
+	if timeZone != "" {
+		description += "（" + timeZone + "）"
+	}
+	return description
+}
+
+// describeCronFields 按Cron表达式的各字段生成描述
+func describeCronFields(cronExpr string) string {
+
 	fields := strings.Fields(cronExpr)
 
 	var second, minute, hour, day, month, weekday string
@@ -1410,47 +1857,68 @@ func (a *App) getScheduledTasksPath() string {
 	return filepath.Join(exeDir, "scheduled_tasks.json")
 }
 
-// saveScheduledTasks 保存定时任务状态到文件
-func (a *App) saveScheduledTasks() error {
-	a.cronMutex.RLock()
-	defer a.cronMutex.RUnlock()
-
-	// 只保存任务ID列表，因为cron表达式已经在任务配置中
-	scheduledTaskIDs := make([]string, 0, len(a.cronJobs))
-	for taskID := range a.cronJobs {
-		scheduledTaskIDs = append(scheduledTaskIDs, taskID)
-	}
+// updateScheduledTaskIDs 在定时任务状态文件中添加或移除一个任务
+// 状态文件由桌面程序和守护进程共用，只在文件锁内修改指定任务，不用本进程的调度列表覆盖整个文件
+func (a *App) updateScheduledTaskIDs(taskID string, scheduled bool) error {
+	path := a.getScheduledTasksPath()
+	return withFileLock(path, func() error {
+		scheduledTaskIDs, err := a.readScheduledTaskIDs()
+		if err != nil {
+			return err
+		}
 
-	data, err := json.MarshalIndent(scheduledTaskIDs, "", "  ")
-	if err != nil {
-		return err
-	}
+		// 只保存任务ID列表，因为cron表达式已经在任务配置中
+		updated := make([]string, 0, len(scheduledTaskIDs)+1)
+		for _, id := range scheduledTaskIDs {
+			if id != taskID {
+				updated = append(updated, id)
+			}
+		}
+		if scheduled {
+			updated = append(updated, taskID)
+		}
+		if len(updated) == len(scheduledTaskIDs) && !scheduled {
+			return nil
+		}
 
-	return os.WriteFile(a.getScheduledTasksPath(), data, 0644)
+		data, err := json.MarshalIndent(updated, "", "  ")
+		if err != nil {
+			return err
+		}
+		return writeFileAtomic(path, data)
+	})
 }
 
-// restoreScheduledTasks 从文件恢复定时任务状态
-func (a *App) restoreScheduledTasks() {
-	scheduledPath := a.getScheduledTasksPath()
-
-	// 检查文件是否存在
-	if _, err := os.Stat(scheduledPath); os.IsNotExist(err) {
-		return // 文件不存在，跳过恢复
+// readScheduledTaskIDs 读取保存的定时任务ID列表（文件不存在时返回空列表）
+func (a *App) readScheduledTaskIDs() ([]string, error) {
+	data, err := os.ReadFile(a.getScheduledTasksPath())
+	if os.IsNotExist(err) {
+		return []string{}, nil
 	}
-
-	data, err := os.ReadFile(scheduledPath)
 	if err != nil {
-		return // 读取失败，跳过恢复
+		return nil, err
 	}
 
 	var scheduledTaskIDs []string
 	if err := json.Unmarshal(data, &scheduledTaskIDs); err != nil {
-		return // 解析失败，跳过恢复
+		return nil, err
+	}
+	return scheduledTaskIDs, nil
+}
+
+// restoreScheduledTasks 从文件恢复定时任务状态
+func (a *App) restoreScheduledTasks() {
+	scheduledTaskIDs, err := a.readScheduledTaskIDs()
+	if err != nil || len(scheduledTaskIDs) == 0 {
+		return // 文件不存在或读取失败，跳过恢复
 	}
 
 	// 等待任务缓存加载完成
 	time.Sleep(1 * time.Second)
 
+	// 守护进程运行时只记录调度状态，不在本地触发
+	delegated := a.schedulingDelegated()
+
 	// 恢复每个定时任务
 	for _, taskID := range scheduledTaskIDs {
 		a.cacheMutex.RLock()
@@ -1461,27 +1929,25 @@ func (a *App) restoreScheduledTasks() {
 			continue // 任务不存在或没有cron表达式，跳过
 		}
 
+		if delegated {
+			a.cronMutex.Lock()
+			a.cronJobs[taskID] = delegatedEntryID
+			a.cronMutex.Unlock()
+			continue
+		}
+
 		// 重新添加定时任务
 		a.cronMutex.Lock()
-		entryID, err := a.cronScheduler.AddFunc(task.CronExpr, func() {
-			// 更新最后执行时间和状态
-			a.updateLastRunInfo(taskID, "running", "定时任务执行中...")
-
-			// 执行任务
-			success, _, result := a.runTaskWithResult(task)
-
-			// 更新执行结果
-			status := "success"
-			if !success {
-				status = "failed"
-			}
-			a.updateLastRunInfo(taskID, status, result)
-		})
-
+		entryID, err := a.addCronEntry(task)
 		if err == nil {
 			a.cronJobs[taskID] = entryID
 		}
 		a.cronMutex.Unlock()
+
+		// 补偿应用关闭期间错过的执行
+		if err == nil {
+			a.catchUpMissedRuns(taskID)
+		}
 	}
 }
 
@@ -1583,25 +2049,8 @@ func (a *App) makeDetailedRequestWithResult(task *Task) TestTaskResult {
 		ResponseHeaders: make(map[string]string),
 	}
 
-	// 检查敏感headers
-	sensitiveHeaders := []string{}
-	for key, value := range task.Headers {
-		lowerKey := strings.ToLower(key)
-		if strings.Contains(lowerKey, "cookie") ||
-			strings.Contains(lowerKey, "authorization") ||
-			strings.Contains(lowerKey, "token") {
-			sensitiveHeaders = append(sensitiveHeaders, key)
-			// 脱敏处理
-			if len(value) > 20 {
-				result.RequestHeaders[key] = value[:10] + "***" + value[len(value)-7:]
-			} else {
-				result.RequestHeaders[key] = "***"
-			}
-		} else {
-			result.RequestHeaders[key] = value
-		}
-	}
-	result.SensitiveHeaders = sensitiveHeaders
+	// 检查敏感headers并脱敏
+	result.RequestHeaders, result.SensitiveHeaders = maskSensitiveHeaders(task.Headers)
 
 	var body io.Reader
 	if task.Data != "" {
@@ -1630,13 +2079,10 @@ func (a *App) makeDetailedRequestWithResult(task *Task) TestTaskResult {
 		}
 	}
 
-	// 创建HTTP客户端
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-		// 不跟随重定向，让用户看到原始响应
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse
-		},
+	// 创建HTTP客户端，不跟随重定向，让用户看到原始响应
+	client := a.newHTTPClient(task)
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
 	}
 
 	resp, err := client.Do(req)
@@ -1721,108 +2167,191 @@ func (a *App) getExecutionLogsPath() string {
 	return filepath.Join(exeDir, "execution_logs.json")
 }
 
-// saveTaskLogs 保存任务级别日志到文件
+// saveInBackground 在后台执行保存操作，退出前通过 waitForPendingSaves 等待完成
+func (a *App) saveInBackground(save func() error) {
+	a.pendingSaves.Add(1)
+	go func() {
+		defer a.pendingSaves.Done()
+		if err := save(); err != nil {
+			fmt.Printf("后台保存失败: %v\n", err)
+		}
+	}()
+}
+
+// waitForPendingSaves 等待所有后台保存操作完成
+func (a *App) waitForPendingSaves() {
+	a.pendingSaves.Wait()
+}
+
+// saveTaskLogs 保存任务级别日志到文件（写入前在文件锁内合并其他进程写入的日志）
 func (a *App) saveTaskLogs() error {
-	a.logMutex.RLock()
-	defer a.logMutex.RUnlock()
+	a.logFileMutex.Lock()
+	defer a.logFileMutex.Unlock()
 
-	data, err := json.MarshalIndent(a.taskLogs, "", "  ")
-	if err != nil {
-		return err
-	}
+	path := a.getTaskLogsPath()
+	return withFileLock(path, func() error {
+		if err := a.mergeTaskLogsFile(path); err != nil {
+			return err
+		}
+
+		a.logMutex.RLock()
+		data, err := json.MarshalIndent(a.taskLogs, "", "  ")
+		saved := taskLogIDs(a.taskLogs)
+		a.logMutex.RUnlock()
+		if err != nil {
+			return err
+		}
+		if err := writeFileAtomic(path, data); err != nil {
+			return err
+		}
 
-	return os.WriteFile(a.getTaskLogsPath(), data, 0644)
+		a.logMutex.Lock()
+		a.taskLogsSnapshot = saved
+		a.logMutex.Unlock()
+		if info, err := os.Stat(path); err == nil {
+			a.taskLogsFile = info
+		}
+		return nil
+	})
 }
 
-// saveExecutionLogs 保存详细执行日志到文件
+// saveExecutionLogs 保存详细执行日志到文件（写入前在文件锁内合并其他进程写入的日志）
 func (a *App) saveExecutionLogs() error {
-	a.logMutex.RLock()
-	defer a.logMutex.RUnlock()
+	a.logFileMutex.Lock()
+	defer a.logFileMutex.Unlock()
 
-	data, err := json.MarshalIndent(a.executionLogs, "", "  ")
-	if err != nil {
-		return err
-	}
+	path := a.getExecutionLogsPath()
+	return withFileLock(path, func() error {
+		if err := a.mergeExecutionLogsFile(path); err != nil {
+			return err
+		}
+
+		a.logMutex.RLock()
+		data, err := json.MarshalIndent(a.executionLogs, "", "  ")
+		saved := executionLogIDs(a.executionLogs)
+		a.logMutex.RUnlock()
+		if err != nil {
+			return err
+		}
+		if err := writeFileAtomic(path, data); err != nil {
+			return err
+		}
 
-	return os.WriteFile(a.getExecutionLogsPath(), data, 0644)
+		a.logMutex.Lock()
+		a.executionLogsSnapshot = saved
+		a.logMutex.Unlock()
+		if info, err := os.Stat(path); err == nil {
+			a.executionLogsFile = info
+		}
+		return nil
+	})
 }
 
 // loadHistoryLogs 加载历史日志数据
 func (a *App) loadHistoryLogs() {
-	// 加载任务级别日志
-	taskLogsPath := a.getTaskLogsPath()
-	if data, err := os.ReadFile(taskLogsPath); err == nil {
-		var taskLogs map[string][]TaskLogEntry
-		if err := json.Unmarshal(data, &taskLogs); err == nil {
-			a.logMutex.Lock()
-			a.taskLogs = taskLogs
-			a.logMutex.Unlock()
-		}
-	}
+	// 与加载前已写入的日志合并
+	a.syncLogsWithDisk()
 
-	// 加载详细执行日志
-	executionLogsPath := a.getExecutionLogsPath()
-	if data, err := os.ReadFile(executionLogsPath); err == nil {
-		var executionLogs map[string]ExecutionLog
-		if err := json.Unmarshal(data, &executionLogs); err == nil {
-			a.logMutex.Lock()
-			a.executionLogs = executionLogs
-			a.logMutex.Unlock()
-		}
-	}
+	// 补录历史执行记录（用于趋势统计）
+	a.backfillRunHistory()
 
-	// 清理过期日志（保留最近30天）
-	a.cleanupOldLogs()
+	// 按保留策略清理过期日志
+	a.runLogCleanup("startup")
 }
 
-// cleanupOldLogs 清理过期日志
-func (a *App) cleanupOldLogs() {
+// cleanupOldLogs 按保留策略清理日志，返回删除的任务日志数、详细日志数和处理的响应内容数
+func (a *App) cleanupOldLogs() (int, int, int) {
+	a.logMutex.RLock()
+	taskIDs := make([]string, 0, len(a.taskLogs))
+	for taskID := range a.taskLogs {
+		taskIDs = append(taskIDs, taskID)
+	}
+	a.logMutex.RUnlock()
+
+	// 先获取各任务的保留策略，避免持有日志锁时再获取缓存锁
+	policies := make(map[string]RetentionPolicy, len(taskIDs))
+	for _, taskID := range taskIDs {
+		policies[taskID] = a.getRetentionPolicy(taskID)
+	}
+	globalPolicy := a.getSettings().Retention
+
 	a.logMutex.Lock()
 	defer a.logMutex.Unlock()
 
-	cutoffTime := time.Now().AddDate(0, 0, -30) // 30天前
+	removedTaskLogs, removedExecutionLogs, truncatedResponses := 0, 0, 0
+	logPolicies := make(map[string]RetentionPolicy)
 
 	// 清理任务级别日志
 	for taskID, logs := range a.taskLogs {
+		policy, exists := policies[taskID]
+		if !exists {
+			policy = globalPolicy
+		}
+
 		var filteredLogs []TaskLogEntry
-		for _, log := range logs {
-			if logTime, err := time.Parse("2006-01-02 15:04:05", log.Timestamp); err == nil {
-				if logTime.After(cutoffTime) {
+		if policy.MaxAgeDays > 0 {
+			cutoffTime := time.Now().AddDate(0, 0, -policy.MaxAgeDays)
+			for _, log := range logs {
+				if logTime, err := time.ParseInLocation("2006-01-02 15:04:05", log.Timestamp, time.Local); err == nil {
+					if logTime.After(cutoffTime) {
+						filteredLogs = append(filteredLogs, log)
+					}
+				} else {
+					// 如果解析时间失败，保留日志
 					filteredLogs = append(filteredLogs, log)
 				}
-			} else {
-				// 如果解析时间失败，保留日志
-				filteredLogs = append(filteredLogs, log)
 			}
+		} else {
+			filteredLogs = logs
+		}
+
+		if policy.MaxRuns > 0 && len(filteredLogs) > policy.MaxRuns {
+			filteredLogs = filteredLogs[len(filteredLogs)-policy.MaxRuns:]
 		}
 
+		removedTaskLogs += len(logs) - len(filteredLogs)
 		if len(filteredLogs) == 0 {
 			delete(a.taskLogs, taskID)
-		} else {
-			a.taskLogs[taskID] = filteredLogs
+			continue
 		}
-	}
+		a.taskLogs[taskID] = filteredLogs
 
-	// 清理详细执行日志（基于任务级别日志的存在性）
-	for logID := range a.executionLogs {
-		// 检查对应的任务级别日志是否还存在
-		found := false
-		for _, logs := range a.taskLogs {
-			for _, log := range logs {
-				if log.ID == logID {
-					found = true
-					break
-				}
-			}
-			if found {
-				break
-			}
+		for _, log := range filteredLogs {
+			logPolicies[log.ID] = policy
 		}
+	}
 
+	// 清理详细执行日志（基于任务级别日志的存在性），并按策略处理响应内容
+	for logID, executionLog := range a.executionLogs {
+		policy, found := logPolicies[logID]
 		if !found {
 			delete(a.executionLogs, logID)
+			removedExecutionLogs++
+			continue
+		}
+
+		// 读取方可能在锁外持有原切片，修改时复制到新的切片和日志中
+		var detailedLogs []DetailedLogEntry
+		for i, entry := range executionLog.DetailedLogs {
+			retained := retainResponseBody(entry.Response, entry.Success, policy)
+			if retained == entry.Response {
+				continue
+			}
+			if detailedLogs == nil {
+				detailedLogs = make([]DetailedLogEntry, len(executionLog.DetailedLogs))
+				copy(detailedLogs, executionLog.DetailedLogs)
+			}
+			detailedLogs[i].Response = retained
+			truncatedResponses++
+		}
+		if detailedLogs != nil {
+			updated := executionLog
+			updated.DetailedLogs = detailedLogs
+			a.executionLogs[logID] = updated
 		}
 	}
+
+	return removedTaskLogs, removedExecutionLogs, truncatedResponses
 }
 
 // ==================== 环境变量管理 ====================
@@ -1916,32 +2445,46 @@ func (a *App) SetEnvVariable(key, value string) string {
 
 // SetEnvVariableWithSeparator 设置环境变量（支持分隔符）
 func (a *App) SetEnvVariableWithSeparator(key, dataJson string) string {
+	if err := a.setEnvVariable(key, dataJson); err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("环境变量 '%s' 设置成功", key)
+}
+
+// setEnvVariable 设置环境变量（dataJson 为 EnvVariableData）
+func (a *App) setEnvVariable(key, dataJson string) error {
 	if key == "" {
-		return "错误：变量名不能为空"
+		return inputErrorf("错误：变量名不能为空")
 	}
 
 	var data EnvVariableData
 	if err := json.Unmarshal([]byte(dataJson), &data); err != nil {
-		return fmt.Sprintf("数据格式错误：%v", err)
+		return inputErrorf("数据格式错误：%v", err)
 	}
 
 	if err := a.dbSetEnvVariable(key, data); err != nil {
-		return fmt.Sprintf("保存失败：%v", err)
+		return fmt.Errorf("保存失败：%v", err)
 	}
-
-	return fmt.Sprintf("环境变量 '%s' 设置成功", key)
+	return nil
 }
 
 // DeleteEnvVariable 删除环境变量
 func (a *App) DeleteEnvVariable(key string) string {
+	if err := a.deleteEnvVariable(key); err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("环境变量 '%s' 删除成功", key)
+}
+
+// deleteEnvVariable 删除环境变量
+func (a *App) deleteEnvVariable(key string) error {
 	if err := a.dbDeleteEnvVariable(key); err != nil {
-		if err.Error() == "变量不存在" {
-			return "错误：变量不存在"
+		if errors.Is(err, errVariableNotFound) {
+			return err
 		}
-		return fmt.Sprintf("删除失败：%v", err)
+		return fmt.Errorf("删除失败：%v", err)
 	}
-
-	return fmt.Sprintf("环境变量 '%s' 删除成功", key)
+	return nil
 }
 
 // UpdateEnvVariable 更新环境变量（向后兼容）
@@ -2221,9 +2764,12 @@ func (a *App) evaluateSuccessConditionWithDetails(task *Task, resp *http.Respons
 	// 如果没有启用自定义成功条件，使用默认的HTTP状态码判断
 	if !task.SuccessCondition.Enabled {
 		fmt.Printf("未启用自定义成功条件，使用HTTP状态码判断\n")
-		result := resp.StatusCode >= 200 && resp.StatusCode < 300
+		result := isExpectedStatus(task.SuccessCondition, resp.StatusCode)
 		details.Type = "http_status"
 		details.ActualValue = fmt.Sprintf("%d", resp.StatusCode)
+		if len(task.SuccessCondition.StatusCodes) > 0 {
+			details.ExpectedValue = formatStatusCodes(task.SuccessCondition.StatusCodes)
+		}
 		details.Result = result
 		details.Reason = "未启用自定义成功条件，使用HTTP状态码判断"
 		return result, details
@@ -2238,9 +2784,12 @@ func (a *App) evaluateSuccessConditionWithDetails(task *Task, resp *http.Respons
 	// 如果没有设置JSON路径，使用默认判断
 	if task.SuccessCondition.JsonPath == "" {
 		fmt.Printf("JSON路径为空，使用HTTP状态码判断\n")
-		result := resp.StatusCode >= 200 && resp.StatusCode < 300
+		result := isExpectedStatus(task.SuccessCondition, resp.StatusCode)
 		details.Type = "http_status"
 		details.ActualValue = fmt.Sprintf("%d", resp.StatusCode)
+		if len(task.SuccessCondition.StatusCodes) > 0 {
+			details.ExpectedValue = formatStatusCodes(task.SuccessCondition.StatusCodes)
+		}
 		details.Result = result
 		details.Reason = "JSON路径为空，使用HTTP状态码判断"
 		return result, details
@@ -2313,6 +2862,28 @@ func (a *App) evaluateSuccessConditionWithDetails(task *Task, resp *http.Respons
 	return result, details
 }
 
+// isExpectedStatus 判断状态码是否符合期望，未指定期望状态码时接受任意2xx
+func isExpectedStatus(condition SuccessCondition, statusCode int) bool {
+	if len(condition.StatusCodes) == 0 {
+		return statusCode >= 200 && statusCode < 300
+	}
+	for _, code := range condition.StatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// formatStatusCodes 将状态码列表格式化为文本
+func formatStatusCodes(codes []int) string {
+	parts := make([]string, 0, len(codes))
+	for _, code := range codes {
+		parts = append(parts, strconv.Itoa(code))
+	}
+	return strings.Join(parts, ", ")
+}
+
 // generateConditionFailureDescription 生成成功条件失败的详细描述
 func (a *App) generateConditionFailureDescription(details *SuccessConditionDetails) string {
 	if details == nil {
@@ -2600,6 +3171,47 @@ func (a *App) initDatabase() error {
 	);
 
 	CREATE INDEX IF NOT EXISTS idx_env_variables_key ON env_variables(key);
+
+	CREATE TABLE IF NOT EXISTS run_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		task_id TEXT NOT NULL,
+		task_log_id TEXT NOT NULL UNIQUE,
+		status TEXT DEFAULT '',
+		started_at INTEGER NOT NULL,
+		duration INTEGER DEFAULT 0,
+		total_requests INTEGER DEFAULT 0,
+		success_count INTEGER DEFAULT 0,
+		failed_count INTEGER DEFAULT 0,
+		avg_latency REAL DEFAULT 0,
+		p95_latency INTEGER DEFAULT 0,
+		max_latency INTEGER DEFAULT 0
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_run_history_task_time ON run_history(task_id, started_at);
+
+	CREATE TABLE IF NOT EXISTS schedule_state (
+		task_id TEXT PRIMARY KEY,
+		last_fire_at INTEGER NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS schedule_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		task_id TEXT NOT NULL,
+		scheduled_at INTEGER NOT NULL,
+		fired_at INTEGER NOT NULL,
+		action TEXT NOT NULL,
+		reason TEXT NOT NULL DEFAULT '',
+		task_log_id TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL DEFAULT ''
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_schedule_history_task ON schedule_history(task_id, id);
+
+	CREATE TABLE IF NOT EXISTS app_settings (
+		key TEXT PRIMARY KEY,
+		value TEXT NOT NULL,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
 	`
 
 	if _, err := db.Exec(createTableSQL); err != nil {
@@ -2611,6 +3223,9 @@ func (a *App) initDatabase() error {
 		fmt.Printf("数据迁移警告: %v\n", err)
 	}
 
+	// 加载全局设置
+	a.loadSettings()
+
 	return nil
 }
 
@@ -2697,7 +3312,7 @@ func (a *App) dbDeleteEnvVariable(key string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("变量不存在")
+		return errVariableNotFound
 	}
 
 	return nil
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// CaptureConfig 详细日志的完整请求/响应采集配置
+type CaptureConfig struct {
+	Enabled              bool `json:"enabled"`              // 是否采集实际发送的请求头、请求体和响应头
+	CaptureRequestBody   bool `json:"captureRequestBody"`   // 是否采集请求体
+	MaxRequestBodyBytes  int  `json:"maxRequestBodyBytes"`  // 请求体最多保存的字节数
+	MaskSensitiveHeaders bool `json:"maskSensitiveHeaders"` // 是否对敏感请求头/响应头脱敏
+}
+
+// defaultCaptureConfig 默认采集配置（默认关闭，避免日志体积膨胀）
+func defaultCaptureConfig() CaptureConfig {
+	return CaptureConfig{
+		Enabled:              false,
+		CaptureRequestBody:   true,
+		MaxRequestBodyBytes:  1024 * 10,
+		MaskSensitiveHeaders: true,
+	}
+}
+
+// validate 校验采集配置
+func (c CaptureConfig) validate() error {
+	if c.MaxRequestBodyBytes < 0 {
+		return fmt.Errorf("请求体大小限制不能为负数")
+	}
+	return nil
+}
+
+// getCaptureConfig 获取任务生效的采集配置（任务未单独设置时使用全局配置）
+func (a *App) getCaptureConfig(task *Task) CaptureConfig {
+	if task.Capture != nil {
+		return *task.Capture
+	}
+	return a.getSettings().Capture
+}
+
+// SetTaskCapture 设置任务的采集配置，configJson 为空时恢复使用全局配置
+func (a *App) SetTaskCapture(taskID, configJson string) string {
+	var config *CaptureConfig
+	if configJson != "" {
+		config = &CaptureConfig{}
+		if err := json.Unmarshal([]byte(configJson), config); err != nil {
+			return fmt.Sprintf("数据格式错误：%v", err)
+		}
+		if err := config.validate(); err != nil {
+			return fmt.Sprintf("错误：%v", err)
+		}
+	}
+
+	a.cacheMutex.Lock()
+	defer a.cacheMutex.Unlock()
+
+	task, exists := a.tasksCache[taskID]
+	if !exists {
+		return "错误：任务不存在"
+	}
+
+	task.Capture = config
+	task.UpdatedAt = time.Now().Unix()
+
+	// 保存到磁盘
+	if err := a.saveTasksToDisk(); err != nil {
+		return fmt.Sprintf("保存失败：%v", err)
+	}
+
+	if config == nil {
+		return fmt.Sprintf("任务 '%s' 已恢复使用全局采集配置", task.Name)
+	}
+	return fmt.Sprintf("任务 '%s' 的采集配置设置成功", task.Name)
+}
+
+// captureExchange 将实际发送的请求和响应头记录到详细日志中
+func captureExchange(entry *DetailedLogEntry, req *http.Request, requestBody string, resp *http.Response, config CaptureConfig) {
+	if !config.Enabled {
+		return
+	}
+
+	if req != nil {
+		entry.RequestHeaders = flattenHeaders(req.Header, req.Host)
+		if config.MaskSensitiveHeaders {
+			entry.RequestHeaders, _ = maskSensitiveHeaders(entry.RequestHeaders)
+		}
+	}
+
+	entry.RequestBodySize = len(requestBody)
+	if config.CaptureRequestBody {
+		entry.RequestBody = truncateUTF8(requestBody, config.MaxRequestBodyBytes)
+	}
+
+	if resp != nil {
+		entry.ResponseHeaders = flattenHeaders(resp.Header, "")
+		if config.MaskSensitiveHeaders {
+			entry.ResponseHeaders, _ = maskSensitiveHeaders(entry.ResponseHeaders)
+		}
+	}
+}
+
+// flattenHeaders 将多值header合并为单个字符串，host 不为空时一并记录Host头
+func flattenHeaders(header http.Header, host string) map[string]string {
+	result := make(map[string]string, len(header)+1)
+	for key, values := range header {
+		result[key] = strings.Join(values, ", ")
+	}
+	if host != "" {
+		if _, exists := result["Host"]; !exists {
+			result["Host"] = host
+		}
+	}
+	return result
+}
+
+// isSensitiveHeader 判断header是否包含敏感信息
+func isSensitiveHeader(key string) bool {
+	lowerKey := strings.ToLower(key)
+	return strings.Contains(lowerKey, "cookie") ||
+		strings.Contains(lowerKey, "authorization") ||
+		strings.Contains(lowerKey, "token")
+}
+
+// maskHeaderValue 对敏感header的值进行脱敏
+func maskHeaderValue(value string) string {
+	if len(value) > 20 {
+		return value[:10] + "***" + value[len(value)-7:]
+	}
+	return "***"
+}
+
+// maskSensitiveHeaders 返回脱敏后的headers副本和敏感header名称列表
+func maskSensitiveHeaders(headers map[string]string) (map[string]string, []string) {
+	masked := make(map[string]string, len(headers))
+	sensitiveHeaders := []string{}
+
+	for key, value := range headers {
+		if isSensitiveHeader(key) {
+			sensitiveHeaders = append(sensitiveHeaders, key)
+			masked[key] = maskHeaderValue(value)
+		} else {
+			masked[key] = value
+		}
+	}
+	sort.Strings(sensitiveHeaders)
+
+	return masked, sensitiveHeaders
+}
+
+// truncateUTF8 按字节数截断字符串，避免切断多字节字符
+func truncateUTF8(text string, maxBytes int) string {
+	if len(text) <= maxBytes {
+		return text
+	}
+	if maxBytes <= 0 {
+		return ""
+	}
+
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut]
+}
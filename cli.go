@@ -0,0 +1,521 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// 命令行模式的退出码
+const (
+	cliExitOK      = 0 // 成功
+	cliExitFailed  = 1 // 任务执行失败或请求失败
+	cliExitUsage   = 2 // 参数错误
+	cliExitRuntime = 3 // 运行环境错误（如读取数据失败）
+)
+
+// cliCommands 命令行模式支持的子命令
+var cliCommands = map[string]func(*cliContext, []string) int{
+	"list":   cliList,
+	"run":    cliRun,
+	"test":   cliTest,
+	"export": cliExport,
+	"daemon": cliDaemon,
+}
+
+// cliContext 命令行模式的运行环境
+type cliContext struct {
+	app *App
+	out io.Writer // 命令输出（标准输出）
+	err io.Writer // 错误输出
+}
+
+// CLIRunResult 命令行模式中单个任务的执行结果
+type CLIRunResult struct {
+	TaskID        string `json:"taskId"`
+	Name          string `json:"name"`
+	TaskLogID     string `json:"taskLogId"`
+	Status        string `json:"status"` // success, partial, failed
+	TotalRequests int    `json:"totalRequests"`
+	SuccessCount  int    `json:"successCount"`
+	FailedCount   int    `json:"failedCount"`
+	Duration      int64  `json:"duration"` // 秒
+}
+
+// isCLIInvocation 判断命令行参数是否要求以命令行模式运行
+func isCLIInvocation(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	if _, exists := cliCommands[args[0]]; exists {
+		return true
+	}
+	switch args[0] {
+	case "help", "-h", "--help", "version", "--version":
+		return true
+	}
+	return false
+}
+
+// runCLI 以命令行模式运行（不创建窗口），返回进程退出码
+func runCLI(args []string) int {
+	attachConsole()
+
+	out := os.Stdout
+	errOut := os.Stderr
+
+	switch args[0] {
+	case "help", "-h", "--help":
+		printCLIUsage(out)
+		return cliExitOK
+	case "version", "--version":
+		fmt.Fprintf(out, "%s %s (%s)\n", AppName, AppVersion, BuildDate)
+		return cliExitOK
+	}
+
+	command := cliCommands[args[0]]
+
+	// 执行过程中的调试输出不应混入命令输出，设置 HTTPTASKRUNNER_VERBOSE=1 时输出到标准错误
+	restore := silenceStdout(os.Getenv("HTTPTASKRUNNER_VERBOSE") != "")
+	defer restore()
+
+	app := NewApp()
+	if app.db == nil {
+		fmt.Fprintln(errOut, "错误：数据库初始化失败")
+		return cliExitRuntime
+	}
+	app.preloadTasks()
+	app.loadHistoryLogs()
+	defer func() {
+		app.waitForPendingSaves()
+		app.closeDatabase()
+	}()
+
+	return command(&cliContext{app: app, out: out, err: errOut}, args[1:])
+}
+
+// silenceStdout 将标准输出重定向到空设备（verbose 时重定向到标准错误），返回恢复函数
+func silenceStdout(verbose bool) func() {
+	original := os.Stdout
+	if verbose {
+		os.Stdout = os.Stderr
+		return func() { os.Stdout = original }
+	}
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return func() {}
+	}
+	os.Stdout = devNull
+	return func() {
+		os.Stdout = original
+		devNull.Close()
+	}
+}
+
+// printCLIUsage 输出命令行帮助
+func printCLIUsage(w io.Writer) {
+	fmt.Fprintf(w, `%s %s - 命令行模式
+
+用法:
+  %[1]s list   [--tag 标签] [--json]
+  %[1]s run    [任务ID或名称...] [--tag 标签]... [--json]
+  %[1]s test   <任务ID或名称> [--json]
+  %[1]s export <执行日志ID或任务ID/名称> --format har|junit|csv|json [--out 文件] [--filter 筛选JSON]
+  %[1]s daemon [--grace 30s]
+
+说明:
+  run     依次执行指定的任务，任一请求失败时退出码为 1
+  test    发送一次测试请求，请求失败时退出码为 1
+  export  导出执行日志；指定任务时导出该任务最近一次执行，未指定 --out 时输出到标准输出
+  daemon  以守护进程运行定时任务，收到 SIGINT/SIGTERM 后等待执行中的任务完成再退出；
+          运行期间桌面程序不会重复触发定时任务，桌面程序中的修改会自动同步
+
+使用与桌面程序相同的 data/ 目录和数据库文件。
+设置环境变量 HTTPTASKRUNNER_VERBOSE=1 可在标准错误中查看详细请求日志。
+
+退出码: 0 成功, 1 任务失败, 2 参数错误, 3 运行环境错误
+`, AppName, AppVersion)
+}
+
+// stringListFlag 可重复指定的字符串参数
+type stringListFlag []string
+
+func (f *stringListFlag) String() string { return strings.Join(*f, ",") }
+
+func (f *stringListFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// parseCLIFlags 解析参数，允许选项和位置参数交替出现，返回位置参数
+func parseCLIFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// newCLIFlagSet 创建子命令的参数解析器
+func (c *cliContext) newCLIFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.err)
+	return fs
+}
+
+// writeJSON 以JSON格式输出
+func (c *cliContext) writeJSON(value interface{}) int {
+	encoder := json.NewEncoder(c.out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		fmt.Fprintf(c.err, "错误：%v\n", err)
+		return cliExitRuntime
+	}
+	return cliExitOK
+}
+
+// sortedTasks 返回按名称排序的任务列表
+func (c *cliContext) sortedTasks() []*Task {
+	c.app.cacheMutex.RLock()
+	tasks := make([]*Task, 0, len(c.app.tasksCache))
+	for _, task := range c.app.tasksCache {
+		tasks = append(tasks, task)
+	}
+	c.app.cacheMutex.RUnlock()
+
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].Name != tasks[j].Name {
+			return tasks[i].Name < tasks[j].Name
+		}
+		return tasks[i].ID < tasks[j].ID
+	})
+	return tasks
+}
+
+// resolveTasks 根据任务ID、名称和标签选择任务（按ID去重，保持指定顺序）
+func (c *cliContext) resolveTasks(selectors, tags []string) ([]*Task, error) {
+	tasks := c.sortedTasks()
+	var result []*Task
+	seen := make(map[string]bool)
+	add := func(task *Task) {
+		if !seen[task.ID] {
+			seen[task.ID] = true
+			result = append(result, task)
+		}
+	}
+
+	for _, selector := range selectors {
+		var matched []*Task
+		for _, task := range tasks {
+			if task.ID == selector {
+				matched = []*Task{task}
+				break
+			}
+			if task.Name == selector {
+				matched = append(matched, task)
+			}
+		}
+		if len(matched) == 0 {
+			return nil, fmt.Errorf("找不到任务：%s", selector)
+		}
+		for _, task := range matched {
+			add(task)
+		}
+	}
+
+	for _, tag := range tags {
+		found := false
+		for _, task := range tasks {
+			for _, taskTag := range task.Tags {
+				if taskTag == tag {
+					add(task)
+					found = true
+					break
+				}
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("没有带标签 '%s' 的任务", tag)
+		}
+	}
+
+	return result, nil
+}
+
+// cliList 列出任务
+func cliList(c *cliContext, args []string) int {
+	fs := c.newCLIFlagSet("list")
+	tag := fs.String("tag", "", "只列出带指定标签的任务")
+	asJSON := fs.Bool("json", false, "以JSON格式输出")
+	if _, err := parseCLIFlags(fs, args); err != nil {
+		return cliExitUsage
+	}
+
+	// 命令行模式不运行调度器，定时状态以桌面程序保存的为准
+	scheduled := make(map[string]bool)
+	scheduledTaskIDs, _ := c.app.readScheduledTaskIDs()
+	for _, taskID := range scheduledTaskIDs {
+		scheduled[taskID] = true
+	}
+
+	var tasks []*Task
+	for _, task := range c.sortedTasks() {
+		if *tag != "" && !containsString(task.Tags, *tag) {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+
+	if *asJSON {
+		if tasks == nil {
+			tasks = []*Task{}
+		}
+		return c.writeJSON(tasks)
+	}
+
+	writer := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "ID\t名称\t方法\tURL\t标签\t定时\t最后执行")
+	for _, task := range tasks {
+		schedule := task.CronExpr
+		if schedule != "" && !scheduled[task.ID] {
+			schedule += "（未启用）"
+		}
+		lastRun := task.LastRunStatus
+		if task.LastRunTime != "" {
+			lastRun = task.LastRunTime + " " + task.LastRunStatus
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			task.ID, task.Name, task.Method, task.URL, strings.Join(task.Tags, ","), schedule, lastRun)
+	}
+	writer.Flush()
+	fmt.Fprintf(c.out, "共 %d 个任务\n", len(tasks))
+	return cliExitOK
+}
+
+// cliRun 执行任务
+func cliRun(c *cliContext, args []string) int {
+	fs := c.newCLIFlagSet("run")
+	var tags stringListFlag
+	fs.Var(&tags, "tag", "执行带指定标签的任务（可重复指定）")
+	asJSON := fs.Bool("json", false, "以JSON格式输出")
+	selectors, err := parseCLIFlags(fs, args)
+	if err != nil {
+		return cliExitUsage
+	}
+	if len(selectors) == 0 && len(tags) == 0 {
+		fmt.Fprintln(c.err, "错误：请指定要执行的任务ID、名称或 --tag")
+		return cliExitUsage
+	}
+
+	tasks, err := c.resolveTasks(selectors, tags)
+	if err != nil {
+		fmt.Fprintf(c.err, "错误：%v\n", err)
+		return cliExitUsage
+	}
+
+	exitCode := cliExitOK
+	results := make([]CLIRunResult, 0, len(tasks))
+	for _, task := range tasks {
+		if !*asJSON {
+			fmt.Fprintf(c.out, "执行任务 '%s' (%s)...\n", task.Name, task.ID)
+		}
+
+		run := c.app.runTask(task, TriggerCLI, c.app.addRun(task.ID))
+		result := CLIRunResult{
+			TaskID:        task.ID,
+			Name:          task.Name,
+			TaskLogID:     run.TaskLogID,
+			Status:        run.Status,
+			TotalRequests: run.Total,
+			SuccessCount:  run.SuccessCount,
+			FailedCount:   run.FailedCount,
+			Duration:      run.Duration,
+		}
+		if result.Status != "success" {
+			exitCode = cliExitFailed
+		}
+		results = append(results, result)
+
+		if !*asJSON {
+			fmt.Fprintf(c.out, "  状态: %s，成功: %d/%d，耗时: %d秒，日志ID: %s\n",
+				result.Status, result.SuccessCount, result.TotalRequests, result.Duration, result.TaskLogID)
+		}
+	}
+
+	if *asJSON {
+		if code := c.writeJSON(results); code != cliExitOK {
+			return code
+		}
+	}
+	return exitCode
+}
+
+// cliTest 发送一次测试请求
+func cliTest(c *cliContext, args []string) int {
+	fs := c.newCLIFlagSet("test")
+	asJSON := fs.Bool("json", false, "以JSON格式输出")
+	selectors, err := parseCLIFlags(fs, args)
+	if err != nil {
+		return cliExitUsage
+	}
+	if len(selectors) != 1 {
+		fmt.Fprintln(c.err, "错误：请指定一个任务ID或名称")
+		return cliExitUsage
+	}
+
+	tasks, err := c.resolveTasks(selectors, nil)
+	if err != nil {
+		fmt.Fprintf(c.err, "错误：%v\n", err)
+		return cliExitUsage
+	}
+	if len(tasks) > 1 {
+		fmt.Fprintf(c.err, "错误：有 %d 个任务名为 '%s'，请使用任务ID\n", len(tasks), selectors[0])
+		return cliExitUsage
+	}
+
+	result := c.app.TestTaskWithBackend(tasks[0].ID)
+	exitCode := cliExitOK
+	if !result.Success {
+		exitCode = cliExitFailed
+	}
+
+	if *asJSON {
+		if code := c.writeJSON(result); code != cliExitOK {
+			return code
+		}
+		return exitCode
+	}
+
+	fmt.Fprintf(c.out, "%s %s\n", result.RequestMethod, result.RequestURL)
+	if result.StatusCode > 0 {
+		status := result.StatusText
+		if status == "" {
+			status = fmt.Sprintf("%d", result.StatusCode)
+		}
+		fmt.Fprintf(c.out, "状态: %s，耗时: %dms\n", status, result.ResponseTime)
+	}
+	if result.Success {
+		fmt.Fprintln(c.out, "结果: 成功")
+	} else {
+		fmt.Fprintln(c.out, "结果: 失败")
+		if result.Error != "" {
+			fmt.Fprintf(c.out, "错误: %s\n", result.Error)
+		}
+		if details := result.SuccessConditionDetails; details != nil && details.Reason != "" {
+			fmt.Fprintf(c.out, "成功条件: %s（实际值: %s）\n", details.Reason, details.ActualValue)
+		}
+	}
+	if result.ResponseBody != "" {
+		fmt.Fprintf(c.out, "\n%s\n", result.ResponseBody)
+	}
+	return exitCode
+}
+
+// cliExport 导出执行日志
+func cliExport(c *cliContext, args []string) int {
+	fs := c.newCLIFlagSet("export")
+	format := fs.String("format", "json", "导出格式: har, junit, csv, json")
+	out := fs.String("out", "", "输出文件（为空时输出到标准输出）")
+	filter := fs.String("filter", "", "筛选条件JSON，如 {\"status\":\"failed\"}")
+	selectors, err := parseCLIFlags(fs, args)
+	if err != nil {
+		return cliExitUsage
+	}
+	if len(selectors) != 1 {
+		fmt.Fprintln(c.err, "错误：请指定一个执行日志ID或任务ID/名称")
+		return cliExitUsage
+	}
+
+	taskLogID, err := c.resolveTaskLogID(selectors[0])
+	if err != nil {
+		fmt.Fprintf(c.err, "错误：%v\n", err)
+		return cliExitUsage
+	}
+
+	executionLog, err := c.app.getExportExecutionLog(taskLogID, *filter)
+	if err != nil {
+		fmt.Fprintf(c.err, "错误：%v\n", err)
+		return cliExitUsage
+	}
+
+	var data []byte
+	switch *format {
+	case "har":
+		data, err = json.MarshalIndent(buildHAR(executionLog), "", "  ")
+	case "junit":
+		data, err = buildJUnitReport(executionLog, c.app.taskNameForLog(taskLogID))
+	case "csv":
+		data, err = buildCSVReport(executionLog)
+	case "json":
+		data, err = json.MarshalIndent(executionLog, "", "  ")
+	default:
+		fmt.Fprintf(c.err, "错误：不支持的导出格式：%s\n", *format)
+		return cliExitUsage
+	}
+	if err != nil {
+		fmt.Fprintf(c.err, "导出失败：%v\n", err)
+		return cliExitRuntime
+	}
+
+	if *out == "" {
+		c.out.Write(data)
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			fmt.Fprintln(c.out)
+		}
+		return cliExitOK
+	}
+
+	if err := writeExportFile(*out, data); err != nil {
+		fmt.Fprintf(c.err, "导出失败：%v\n", err)
+		return cliExitRuntime
+	}
+	fmt.Fprintf(c.err, "已导出 %d 条请求到 %s\n", len(executionLog.DetailedLogs), *out)
+	return cliExitOK
+}
+
+// resolveTaskLogID 解析执行日志ID，指定任务时返回该任务最近一次执行的日志ID
+func (c *cliContext) resolveTaskLogID(selector string) (string, error) {
+	c.app.logMutex.RLock()
+	_, exists := c.app.executionLogs[selector]
+	c.app.logMutex.RUnlock()
+	if exists {
+		return selector, nil
+	}
+
+	tasks, err := c.resolveTasks([]string{selector}, nil)
+	if err != nil {
+		return "", fmt.Errorf("找不到执行日志或任务：%s", selector)
+	}
+	if len(tasks) > 1 {
+		return "", fmt.Errorf("有 %d 个任务名为 '%s'，请使用任务ID", len(tasks), selector)
+	}
+
+	for _, entry := range c.app.GetTaskLogEntries(tasks[0].ID) {
+		if entry.ExecutionLogId != "" {
+			return entry.ExecutionLogId, nil
+		}
+	}
+	return "", fmt.Errorf("任务 '%s' 没有执行日志", tasks[0].Name)
+}
+
+// containsString 判断列表中是否包含指定字符串
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
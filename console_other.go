@@ -0,0 +1,6 @@
+//go:build !windows
+
+package main
+
+// attachConsole 非Windows平台的命令行模式直接使用当前终端
+func attachConsole() {}
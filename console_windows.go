@@ -0,0 +1,25 @@
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// attachConsole 以窗口程序方式构建时没有控制台，命令行模式下附加到父进程的控制台以便输出
+func attachConsole() {
+	// 输出已被重定向（如CI中写入文件或管道）时无需附加
+	if _, err := os.Stdout.Stat(); err == nil {
+		return
+	}
+
+	const attachParentProcess = ^uintptr(0) // ATTACH_PARENT_PROCESS (DWORD)-1
+	attach := syscall.NewLazyDLL("kernel32.dll").NewProc("AttachConsole")
+	if result, _, _ := attach.Call(attachParentProcess); result == 0 {
+		return
+	}
+
+	if console, err := os.OpenFile("CONOUT$", os.O_WRONLY, 0); err == nil {
+		os.Stdout = console
+		os.Stderr = console
+	}
+}
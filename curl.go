@@ -0,0 +1,612 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CurlImportResult cURL命令解析结果
+type CurlImportResult struct {
+	Task     *Task    `json:"task"`     // 解析得到的任务（未保存）
+	Warnings []string `json:"warnings"` // 无法转换或被忽略的选项
+	Error    string   `json:"error"`    // 错误信息
+}
+
+// 需要参数的cURL选项（长选项名）
+var curlOptionsWithValue = map[string]bool{
+	"request": true, "header": true, "data": true, "data-raw": true, "data-binary": true,
+	"data-ascii": true, "data-urlencode": true, "form": true, "form-string": true, "user": true,
+	"cookie": true, "proxy": true, "user-agent": true, "referer": true, "url": true, "json": true,
+	"output": true, "max-time": true, "connect-timeout": true, "cookie-jar": true, "cert": true,
+	"key": true, "cacert": true, "resolve": true, "retry": true, "write-out": true, "range": true,
+	"proxy-user": true, "oauth2-bearer": true, "upload-file": true, "limit-rate": true,
+}
+
+// cURL短选项到长选项的映射
+var curlShortOptions = map[byte]string{
+	'X': "request", 'H': "header", 'd': "data", 'F': "form", 'u': "user", 'b': "cookie",
+	'x': "proxy", 'A': "user-agent", 'e': "referer", 'o': "output", 'm': "max-time",
+	'c': "cookie-jar", 'E': "cert", 'r': "range", 'U': "proxy-user", 'T': "upload-file",
+	'w': "write-out", 'k': "insecure", 'G': "get", 'I': "head", 'L': "location",
+	's': "silent", 'S': "show-error", 'i': "include", 'v': "verbose", 'f': "fail",
+	'g': "globoff", 'N': "no-buffer", 'Z': "parallel",
+}
+
+// 不影响请求内容、可以安全忽略的cURL选项
+var curlIgnoredOptions = map[string]bool{
+	"silent": true, "show-error": true, "include": true, "verbose": true, "location": true,
+	"globoff": true, "no-buffer": true, "fail": true, "http1.1": true, "http2": true,
+	"http2-prior-knowledge": true, "output": true, "write-out": true, "max-time": true,
+	"connect-timeout": true, "retry": true, "parallel": true, "limit-rate": true,
+}
+
+// ParseCurlCommand 解析cURL命令为任务（不保存，用于预览）
+func (a *App) ParseCurlCommand(command string) CurlImportResult {
+	task, warnings, err := a.parseCurlCommand(command)
+	if err != nil {
+		return CurlImportResult{Warnings: []string{}, Error: err.Error()}
+	}
+	return CurlImportResult{Task: task, Warnings: warnings}
+}
+
+// ImportTaskFromCurl 从cURL命令导入任务，name 为空时根据URL生成任务名称
+func (a *App) ImportTaskFromCurl(command, name string, tags []string) string {
+	task, warnings, err := a.parseCurlCommand(command)
+	if err != nil {
+		return fmt.Sprintf("错误：%v", err)
+	}
+
+	if name != "" {
+		task.Name = name
+	}
+	if tags != nil {
+		task.Tags = tags
+	}
+
+	if err := a.addTasks([]*Task{task}); err != nil {
+		return fmt.Sprintf("保存失败：%v", err)
+	}
+
+	if len(warnings) > 0 {
+		return fmt.Sprintf("任务 '%s' 导入成功，以下内容未转换：\n- %s", task.Name, strings.Join(warnings, "\n- "))
+	}
+	return fmt.Sprintf("任务 '%s' 导入成功", task.Name)
+}
+
+// ExportTaskAsCurl 将任务导出为cURL命令，resolveVariables 为 true 时替换环境变量，否则保留 {{变量}} 占位符
+func (a *App) ExportTaskAsCurl(taskID string, resolveVariables bool) string {
+	a.cacheMutex.RLock()
+	task, exists := a.tasksCache[taskID]
+	a.cacheMutex.RUnlock()
+
+	if !exists {
+		return "错误：任务不存在"
+	}
+
+	if resolveVariables {
+		task = a.createTaskWithVariables(task)
+	}
+
+	return buildCurlCommand(task)
+}
+
+// buildCurlCommand 根据任务生成cURL命令
+func buildCurlCommand(task *Task) string {
+	method := strings.ToUpper(task.Method)
+	if method == "" {
+		method = "GET"
+	}
+
+	parts := []string{"curl " + shellQuote(task.URL)}
+
+	// GET无请求体、POST有请求体时cURL会自动推断方法
+	if !(method == "GET" && task.Data == "") && !(method == "POST" && task.Data != "") {
+		parts = append(parts, "-X "+method)
+	}
+
+	for _, header := range orderedTaskHeaders(task) {
+		parts = append(parts, "-H "+shellQuote(header[0]+": "+header[1]))
+	}
+
+	if task.Data != "" {
+		parts = append(parts, "--data-raw "+shellQuote(task.Data))
+	}
+	if task.Insecure {
+		parts = append(parts, "-k")
+	}
+	if task.Proxy != "" {
+		parts = append(parts, "-x "+shellQuote(task.Proxy))
+	}
+
+	return strings.Join(parts, " \\\n  ")
+}
+
+// orderedTaskHeaders 按HeadersText中的顺序返回headers（HeadersText为空时按名称排序）
+func orderedTaskHeaders(task *Task) [][2]string {
+	var headers [][2]string
+	seen := make(map[string]bool)
+
+	for _, line := range strings.Split(task.HeadersText, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !found || key == "" || value == "" {
+			continue
+		}
+		headers = append(headers, [2]string{key, value})
+		seen[key] = true
+	}
+
+	var rest []string
+	for key := range task.Headers {
+		if !seen[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	for _, key := range rest {
+		headers = append(headers, [2]string{key, task.Headers[key]})
+	}
+
+	return headers
+}
+
+// shellQuote 使用单引号对参数进行POSIX shell转义
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// parseCurlCommand 解析cURL命令，返回任务和未能转换的内容说明
+func (a *App) parseCurlCommand(command string) (*Task, []string, error) {
+	args, err := splitShellWords(normalizeCurlCommand(command))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(args) == 0 {
+		return nil, nil, fmt.Errorf("cURL命令为空")
+	}
+	first := strings.ToLower(args[0])
+	if first != "curl" && first != "curl.exe" && !strings.HasSuffix(first, "/curl") {
+		return nil, nil, fmt.Errorf("不是有效的cURL命令")
+	}
+	args = args[1:]
+
+	warnings := []string{}
+	var (
+		method       string
+		rawURL       string
+		headers      [][2]string
+		dataParts    []string
+		formParts    [][2]string
+		useGet       bool
+		useHead      bool
+		compressed   bool
+		insecure     bool
+		proxy        string
+		isJSONOption bool
+	)
+
+	addHeader := func(key, value string) {
+		headers = append(headers, [2]string{key, value})
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		// 位置参数即URL
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			if rawURL == "" {
+				rawURL = arg
+			} else {
+				warnings = append(warnings, fmt.Sprintf("忽略多余的URL：%s", arg))
+			}
+			continue
+		}
+
+		// 解析选项名和可能内联的值
+		var name, value string
+		hasValue := false
+		if strings.HasPrefix(arg, "--") {
+			name = arg[2:]
+			if eq := strings.Index(name, "="); eq >= 0 {
+				name, value, hasValue = name[:eq], name[eq+1:], true
+			}
+		} else {
+			// 短选项，可能是组合形式（如 -sSk）或内联值（如 -XPOST）
+			cluster := arg[1:]
+			for j := 0; j < len(cluster); j++ {
+				long, known := curlShortOptions[cluster[j]]
+				if !known {
+					warnings = append(warnings, fmt.Sprintf("忽略未知选项：-%c", cluster[j]))
+					continue
+				}
+				if curlOptionsWithValue[long] {
+					name = long
+					if j+1 < len(cluster) {
+						value, hasValue = cluster[j+1:], true
+					}
+					break
+				}
+				if j == len(cluster)-1 {
+					name = long
+				} else {
+					switch long {
+					case "insecure":
+						insecure = true
+					case "get":
+						useGet = true
+					case "head":
+						useHead = true
+					}
+				}
+			}
+			if name == "" {
+				continue
+			}
+		}
+
+		if curlOptionsWithValue[name] && !hasValue {
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("选项 --%s 缺少参数", name)
+			}
+			i++
+			value = args[i]
+		}
+
+		switch name {
+		case "request":
+			method = strings.ToUpper(value)
+		case "url":
+			rawURL = value
+		case "header":
+			key, headerValue, found := strings.Cut(value, ":")
+			if !found {
+				// cURL中 "X-Name;" 表示发送空值header
+				key = strings.TrimSuffix(strings.TrimSpace(value), ";")
+				warnings = append(warnings, fmt.Sprintf("忽略空值header：%s", key))
+				continue
+			}
+			addHeader(strings.TrimSpace(key), strings.TrimSpace(headerValue))
+		case "data", "data-ascii", "data-binary", "data-raw":
+			if strings.HasPrefix(value, "@") && name != "data-raw" {
+				warnings = append(warnings, fmt.Sprintf("不支持从文件读取请求体：%s", value))
+				continue
+			}
+			dataParts = append(dataParts, value)
+		case "json":
+			if strings.HasPrefix(value, "@") {
+				warnings = append(warnings, fmt.Sprintf("不支持从文件读取请求体：%s", value))
+				continue
+			}
+			dataParts = append(dataParts, value)
+			isJSONOption = true
+		case "data-urlencode":
+			encoded, err := encodeCurlDataURLEncode(value)
+			if err != nil {
+				warnings = append(warnings, err.Error())
+				continue
+			}
+			dataParts = append(dataParts, encoded)
+		case "form", "form-string":
+			fieldName, fieldValue, found := strings.Cut(value, "=")
+			if !found {
+				warnings = append(warnings, fmt.Sprintf("忽略无效的表单字段：%s", value))
+				continue
+			}
+			if name == "form" && (strings.HasPrefix(fieldValue, "@") || strings.HasPrefix(fieldValue, "<")) {
+				warnings = append(warnings, fmt.Sprintf("不支持上传文件字段：%s", fieldName))
+				continue
+			}
+			if name == "form" {
+				// 去除 ;type=xxx 等附加属性
+				if semi := strings.Index(fieldValue, ";type="); semi >= 0 {
+					fieldValue = fieldValue[:semi]
+				}
+			}
+			formParts = append(formParts, [2]string{fieldName, fieldValue})
+		case "user":
+			addHeader("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(value)))
+		case "oauth2-bearer":
+			addHeader("Authorization", "Bearer "+value)
+		case "cookie":
+			if !strings.Contains(value, "=") {
+				warnings = append(warnings, fmt.Sprintf("不支持从文件读取Cookie：%s", value))
+				continue
+			}
+			addHeader("Cookie", value)
+		case "user-agent":
+			addHeader("User-Agent", value)
+		case "referer":
+			addHeader("Referer", value)
+		case "proxy":
+			proxy = value
+		case "insecure":
+			insecure = true
+		case "compressed":
+			compressed = true
+		case "get":
+			useGet = true
+		case "head":
+			useHead = true
+		default:
+			if !curlIgnoredOptions[name] {
+				warnings = append(warnings, fmt.Sprintf("忽略不支持的选项：--%s", name))
+			}
+		}
+	}
+
+	if rawURL == "" {
+		return nil, nil, fmt.Errorf("cURL命令中没有URL")
+	}
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+
+	body := strings.Join(dataParts, "&")
+
+	if len(formParts) > 0 {
+		if body != "" {
+			warnings = append(warnings, "同时存在 -d 和 -F，已忽略 -d 的内容")
+		}
+		body = buildMultipartBody(formParts)
+		if !hasCurlHeader(headers, "Content-Type") {
+			addHeader("Content-Type", multipartContentType)
+		}
+	}
+
+	if isJSONOption {
+		if !hasCurlHeader(headers, "Content-Type") {
+			addHeader("Content-Type", "application/json")
+		}
+		if !hasCurlHeader(headers, "Accept") {
+			addHeader("Accept", "application/json")
+		}
+	}
+
+	// -G 将请求体作为查询参数附加到URL
+	if useGet && body != "" && len(formParts) == 0 {
+		separator := "?"
+		if strings.Contains(rawURL, "?") {
+			separator = "&"
+		}
+		rawURL += separator + body
+		body = ""
+	}
+
+	if method == "" {
+		switch {
+		case useHead:
+			method = "HEAD"
+		case useGet:
+			method = "GET"
+		case body != "":
+			method = "POST"
+		default:
+			method = "GET"
+		}
+	}
+
+	// cURL的 -d 默认使用表单编码；JSON请求体交给执行时的Content-Type自动识别
+	trimmedBody := strings.TrimSpace(body)
+	isJSONBody := strings.HasPrefix(trimmedBody, "{") || strings.HasPrefix(trimmedBody, "[")
+	if body != "" && !isJSONBody && !hasCurlHeader(headers, "Content-Type") && len(formParts) == 0 && !isJSONOption {
+		addHeader("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	// --compressed 由Go的HTTP客户端自动协商gzip并解压，显式的Accept-Encoding会关闭自动解压
+	if compressed {
+		filtered := headers[:0]
+		for _, header := range headers {
+			if !strings.EqualFold(header[0], "Accept-Encoding") {
+				filtered = append(filtered, header)
+			}
+		}
+		headers = filtered
+	}
+
+	headersText := headersToText(headers)
+
+	now := time.Now().Unix()
+	task := &Task{
+		ID:          newTaskID(),
+		Name:        defaultTaskNameFromURL(method, rawURL),
+		URL:         rawURL,
+		Method:      method,
+		Headers:     a.parseHeadersText(headersText),
+		HeadersText: headersText,
+		Data:        body,
+		Times:       1,
+		Threads:     1,
+		Tags:        []string{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Insecure:    insecure,
+		Proxy:       proxy,
+	}
+
+	return task, warnings, nil
+}
+
+// encodeCurlDataURLEncode 按 --data-urlencode 的规则编码参数
+func encodeCurlDataURLEncode(value string) (string, error) {
+	if eq := strings.Index(value, "="); eq >= 0 {
+		name, content := value[:eq], value[eq+1:]
+		if name == "" {
+			return url.QueryEscape(content), nil
+		}
+		return name + "=" + url.QueryEscape(content), nil
+	}
+	if strings.Contains(value, "@") {
+		return "", fmt.Errorf("不支持从文件读取 --data-urlencode 内容：%s", value)
+	}
+	return url.QueryEscape(value), nil
+}
+
+// hasCurlHeader 判断是否已设置指定header（不区分大小写）
+func hasCurlHeader(headers [][2]string, name string) bool {
+	for _, header := range headers {
+		if strings.EqualFold(header[0], name) {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultTaskNameFromURL 根据请求方法和URL生成默认任务名称
+func defaultTaskNameFromURL(method, rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return method + " " + rawURL
+	}
+
+	path := parsed.Path
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("%s %s%s", method, parsed.Host, path)
+}
+
+// normalizeCurlCommand 处理换行续行符和Windows cmd格式（Chrome "复制为cURL(cmd)"）的转义
+func normalizeCurlCommand(command string) string {
+	command = strings.ReplaceAll(command, "\r\n", "\n")
+	command = strings.ReplaceAll(command, "\\\n", " ")
+	command = strings.ReplaceAll(command, "`\n", " ") // PowerShell续行
+
+	if strings.Contains(command, "^\"") || strings.Contains(command, "^\n") {
+		var builder strings.Builder
+		for i := 0; i < len(command); i++ {
+			if command[i] == '^' && i+1 < len(command) {
+				i++
+				if command[i] == '\n' {
+					builder.WriteByte(' ')
+					continue
+				}
+			}
+			builder.WriteByte(command[i])
+		}
+		command = builder.String()
+	}
+
+	return strings.TrimSpace(command)
+}
+
+// splitShellWords 按shell规则拆分命令行参数（支持单引号、双引号、$'...' 和反斜杠转义）
+func splitShellWords(command string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inWord  bool
+	)
+
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inWord {
+				args = append(args, current.String())
+				current.Reset()
+				inWord = false
+			}
+		case c == '\\':
+			inWord = true
+			if i+1 < len(command) {
+				i++
+				current.WriteByte(command[i])
+			}
+		case c == '\'':
+			inWord = true
+			end := strings.IndexByte(command[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("单引号未闭合")
+			}
+			current.WriteString(command[i+1 : i+1+end])
+			i += end + 1
+		case c == '$' && i+1 < len(command) && command[i+1] == '\'':
+			inWord = true
+			consumed, err := readANSICQuoted(command[i+2:], &current)
+			if err != nil {
+				return nil, err
+			}
+			i += consumed + 1
+		case c == '"':
+			inWord = true
+			closed := false
+			for i++; i < len(command); i++ {
+				if command[i] == '"' {
+					closed = true
+					break
+				}
+				if command[i] == '\\' && i+1 < len(command) && strings.IndexByte("\"\\$`\n", command[i+1]) >= 0 {
+					i++
+				}
+				current.WriteByte(command[i])
+			}
+			if !closed {
+				return nil, fmt.Errorf("双引号未闭合")
+			}
+		default:
+			inWord = true
+			current.WriteByte(c)
+		}
+	}
+
+	if inWord {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
+
+// readANSICQuoted 读取 $'...' 形式的字符串（调用方已跳过 $'），返回消耗的字节数（包含结尾引号）
+func readANSICQuoted(text string, out *strings.Builder) (int, error) {
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c == '\'' {
+			return i + 1, nil
+		}
+		if c != '\\' || i+1 >= len(text) {
+			out.WriteByte(c)
+			continue
+		}
+
+		i++
+		switch text[i] {
+		case 'n':
+			out.WriteByte('\n')
+		case 't':
+			out.WriteByte('\t')
+		case 'r':
+			out.WriteByte('\r')
+		case '0':
+			out.WriteByte(0)
+		case 'x', 'u', 'U':
+			width := map[byte]int{'x': 2, 'u': 4, 'U': 8}[text[i]]
+			end := i + 1
+			for end < len(text) && end-i-1 < width && strings.IndexByte("0123456789abcdefABCDEF", text[end]) >= 0 {
+				end++
+			}
+			code, err := strconv.ParseUint(text[i+1:end], 16, 32)
+			if err != nil {
+				out.WriteByte('\\')
+				out.WriteByte(text[i])
+				continue
+			}
+			if text[i] == 'x' {
+				out.WriteByte(byte(code))
+			} else {
+				out.WriteRune(rune(code))
+			}
+			i = end - 1
+		default:
+			// \\ \' \" 等直接输出被转义的字符
+			out.WriteByte(text[i])
+		}
+	}
+
+	return 0, fmt.Errorf("$'...' 字符串未闭合")
+}
@@ -0,0 +1,389 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// 守护进程心跳间隔，超过 daemonStaleAfter 没有心跳视为守护进程已退出
+const (
+	daemonHeartbeatInterval = 10 * time.Second
+	daemonStaleAfter        = 3 * daemonHeartbeatInterval
+)
+
+// errDaemonLockLost 守护进程锁已被其他守护进程接管（例如本进程长时间挂起导致心跳过期）
+var errDaemonLockLost = errors.New("守护进程锁已被其他进程接管")
+
+// delegatedEntryID 表示调度由守护进程执行、本地未注册（cron的有效EntryID从1开始）
+const delegatedEntryID cron.EntryID = 0
+
+// DaemonStatus 守护进程状态
+type DaemonStatus struct {
+	Running       bool   `json:"running"`       // 心跳是否有效
+	PID           int    `json:"pid"`           // 守护进程ID
+	StartedAt     string `json:"startedAt"`     // 启动时间
+	LastHeartbeat string `json:"lastHeartbeat"` // 最近一次心跳时间
+	Version       string `json:"version"`       // 守护进程版本
+}
+
+// daemonLock 守护进程锁文件内容
+type daemonLock struct {
+	PID       int    `json:"pid"`
+	StartedAt int64  `json:"startedAt"`
+	Heartbeat int64  `json:"heartbeat"`
+	Version   string `json:"version"`
+}
+
+// daemonScheduleState 守护进程已注册的调度（任务ID -> Cron表达式和时区）及上次加载的任务文件
+type daemonScheduleState struct {
+	registered map[string]string
+	tasksFile  os.FileInfo
+}
+
+// GetDaemonStatus 获取守护进程状态（守护进程运行时桌面程序不会触发定时任务）
+func (a *App) GetDaemonStatus() DaemonStatus {
+	return a.readDaemonStatus()
+}
+
+// getDaemonLockPath 获取守护进程锁文件路径
+func (a *App) getDaemonLockPath() string {
+	exePath, err := os.Executable()
+	if err != nil {
+		return "daemon.lock"
+	}
+
+	exeDir := filepath.Dir(exePath)
+	return filepath.Join(exeDir, "daemon.lock")
+}
+
+// readDaemonStatus 读取锁文件判断守护进程是否在运行
+func (a *App) readDaemonStatus() DaemonStatus {
+	var status DaemonStatus
+
+	data, err := os.ReadFile(a.getDaemonLockPath())
+	if err != nil {
+		return status
+	}
+
+	var lock daemonLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return status
+	}
+
+	heartbeat := time.Unix(lock.Heartbeat, 0)
+	status.Running = time.Since(heartbeat) < daemonStaleAfter
+	status.PID = lock.PID
+	status.StartedAt = time.Unix(lock.StartedAt, 0).Format("2006-01-02 15:04:05")
+	status.LastHeartbeat = heartbeat.Format("2006-01-02 15:04:05")
+	status.Version = lock.Version
+	return status
+}
+
+// acquireDaemonLock 持有锁文件的跨进程锁检查是否已有守护进程在运行，没有（或心跳已过期）时写入本进程的锁
+// 检查和写入在同一次加锁中完成，同时启动的两个守护进程只有一个能成功
+func (a *App) acquireDaemonLock(startedAt time.Time) error {
+	path := a.getDaemonLockPath()
+	return withFileLock(path, func() error {
+		if status := a.readDaemonStatus(); status.Running && status.PID != os.Getpid() {
+			return fmt.Errorf("守护进程已在运行（PID %d，最近心跳 %s）", status.PID, status.LastHeartbeat)
+		}
+		return a.writeDaemonLock(startedAt)
+	})
+}
+
+// writeDaemonHeartbeat 更新锁文件中的心跳时间（锁已被其他守护进程接管时返回 errDaemonLockLost）
+func (a *App) writeDaemonHeartbeat(startedAt time.Time) error {
+	path := a.getDaemonLockPath()
+	return withFileLock(path, func() error {
+		if !a.ownsDaemonLock() {
+			return errDaemonLockLost
+		}
+		return a.writeDaemonLock(startedAt)
+	})
+}
+
+// removeDaemonLock 删除守护进程锁文件（锁已被其他守护进程接管时保留）
+func (a *App) removeDaemonLock() {
+	path := a.getDaemonLockPath()
+	err := withFileLock(path, func() error {
+		if !a.ownsDaemonLock() {
+			return nil
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("删除守护进程锁文件失败: %v\n", err)
+	}
+}
+
+// ownsDaemonLock 判断锁文件是否属于当前进程（锁文件不存在时也视为属于当前进程，调用方需持有文件锁）
+func (a *App) ownsDaemonLock() bool {
+	var lock daemonLock
+	exists, err := readJSONFile(a.getDaemonLockPath(), &lock)
+	return err == nil && (!exists || lock.PID == os.Getpid())
+}
+
+// writeDaemonLock 写入当前进程的锁文件（调用方需持有文件锁）
+func (a *App) writeDaemonLock(startedAt time.Time) error {
+	data, err := json.Marshal(daemonLock{
+		PID:       os.Getpid(),
+		StartedAt: startedAt.Unix(),
+		Heartbeat: time.Now().Unix(),
+		Version:   AppVersion,
+	})
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(a.getDaemonLockPath(), data)
+}
+
+// schedulingDelegated 判断定时任务是否应交给守护进程执行
+func (a *App) schedulingDelegated() bool {
+	return !a.isDaemon && a.readDaemonStatus().Running
+}
+
+// watchDaemon 桌面程序跟随守护进程的启停交接定时任务，直到应用关闭
+func (a *App) watchDaemon() {
+	ticker := time.NewTicker(daemonHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.shutdown:
+			return
+		case <-ticker.C:
+			daemonRunning := a.readDaemonStatus().Running
+			a.handoffSchedules(daemonRunning)
+			a.handoffMetricsServer(daemonRunning)
+			// 合并守护进程和命令行写入的日志
+			a.syncLogsWithDisk()
+		}
+	}
+}
+
+// handoffSchedules 守护进程运行时移除本地注册的调度，守护进程退出后在本地重新注册
+func (a *App) handoffSchedules(daemonRunning bool) {
+	var restore []string
+
+	handedOff := false
+	a.cronMutex.Lock()
+	for taskID, entryID := range a.cronJobs {
+		if daemonRunning && entryID != delegatedEntryID {
+			a.cronScheduler.Remove(entryID)
+			a.cronJobs[taskID] = delegatedEntryID
+			handedOff = true
+		} else if !daemonRunning && entryID == delegatedEntryID {
+			restore = append(restore, taskID)
+		}
+	}
+	a.cronMutex.Unlock()
+
+	// 交接前写入最近的触发时间，恢复时重新读取守护进程写入的触发时间
+	if handedOff || len(restore) > 0 {
+		a.flushScheduleFires()
+	}
+
+	for _, taskID := range restore {
+		if _, err := a.registerSchedule(taskID); err != nil {
+			fmt.Printf("守护进程已退出，恢复任务 %s 的本地调度失败: %v\n", taskID, err)
+			continue
+		}
+		a.catchUpMissedRuns(taskID)
+	}
+}
+
+// hasRunningTasks 判断是否有正在执行的任务
+func (a *App) hasRunningTasks() bool {
+	a.taskMutex.RLock()
+	defer a.taskMutex.RUnlock()
+	return len(a.runningTasks) > 0
+}
+
+// cliDaemon 以守护进程模式运行定时调度，收到 SIGINT/SIGTERM 后退出
+func cliDaemon(c *cliContext, args []string) int {
+	fs := c.newCLIFlagSet("daemon")
+	grace := fs.Duration("grace", 30*time.Second, "收到停止信号后等待执行中任务完成的时间")
+	if _, err := parseCLIFlags(fs, args); err != nil {
+		return cliExitUsage
+	}
+
+	a := c.app
+	startedAt := time.Now()
+	if err := a.acquireDaemonLock(startedAt); err != nil {
+		fmt.Fprintf(c.err, "错误：%v\n", err)
+		return cliExitRuntime
+	}
+	a.isDaemon = true
+	defer a.removeDaemonLock()
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	a.loadEnvVariables()
+	a.cronScheduler.Start()
+	go a.logCleanupLoop()
+	a.startHTTPServices()
+	defer a.stopHTTPServices()
+
+	state := &daemonScheduleState{registered: make(map[string]string)}
+	a.syncDaemonSchedules(state)
+	for taskID := range state.registered {
+		a.catchUpMissedRuns(taskID)
+	}
+	fmt.Fprintf(c.err, "[%s] 守护进程已启动（PID %d），已加载 %d 个定时任务\n",
+		time.Now().Format("2006-01-02 15:04:05"), os.Getpid(), len(state.registered))
+
+	ticker := time.NewTicker(daemonHeartbeatInterval)
+	defer ticker.Stop()
+
+	var received os.Signal
+	lockLost := false
+	for received == nil && !lockLost {
+		select {
+		case received = <-signals:
+		case <-ticker.C:
+			if err := a.writeDaemonHeartbeat(startedAt); err == errDaemonLockLost {
+				// 其他守护进程已接管调度，继续运行会重复执行定时任务
+				lockLost = true
+				continue
+			} else if err != nil {
+				fmt.Fprintf(c.err, "写入心跳失败: %v\n", err)
+			}
+			a.syncDaemonSchedules(state)
+			a.handoffMetricsServer(false)
+			a.syncLogsWithDisk()
+		}
+	}
+
+	if lockLost {
+		fmt.Fprintf(c.err, "[%s] %v，停止调度\n", time.Now().Format("2006-01-02 15:04:05"), errDaemonLockLost)
+	} else {
+		fmt.Fprintf(c.err, "[%s] 收到信号 %v，停止调度\n", time.Now().Format("2006-01-02 15:04:05"), received)
+	}
+	close(a.shutdown)
+	a.cronScheduler.Stop()
+
+	if interrupted := a.waitForRunningTasks(*grace, signals); len(interrupted) > 0 {
+		fmt.Fprintf(c.err, "等待超时，已中断 %d 个执行中的任务\n", len(interrupted))
+	}
+	a.flushScheduleFires()
+
+	fmt.Fprintf(c.err, "[%s] 守护进程已停止\n", time.Now().Format("2006-01-02 15:04:05"))
+	if lockLost {
+		return cliExitRuntime
+	}
+	return cliExitOK
+}
+
+// syncDaemonSchedules 按磁盘上的任务和调度状态更新守护进程的定时任务，使桌面程序的修改生效
+func (a *App) syncDaemonSchedules(state *daemonScheduleState) {
+	// 任务文件变化时合并桌面程序的修改（执行中的运行仍使用原任务对象）
+	// 调度在触发时按ID读取任务，只有Cron表达式变化的任务需要重新注册
+	if info, err := os.Stat(a.getTasksPath()); err == nil && !fileUnchanged(state.tasksFile, info) {
+		a.preloadTasks()
+		a.pruneMetrics()
+		state.tasksFile = info
+	}
+
+	scheduledTaskIDs, err := a.readScheduledTaskIDs()
+	if err != nil {
+		fmt.Printf("读取定时任务状态失败: %v\n", err)
+		return
+	}
+
+	desired := make(map[string]string)
+	a.cacheMutex.RLock()
+	for _, taskID := range scheduledTaskIDs {
+		if task, exists := a.tasksCache[taskID]; exists && task.CronExpr != "" {
+			desired[taskID] = task.CronExpr + " " + scheduleTimeZone(task.Schedule)
+		}
+	}
+	a.cacheMutex.RUnlock()
+
+	for taskID, spec := range state.registered {
+		if desired[taskID] != spec {
+			a.removeCronJob(taskID)
+			delete(state.registered, taskID)
+		}
+	}
+	for taskID, spec := range desired {
+		if _, exists := state.registered[taskID]; exists {
+			continue
+		}
+		// 只读取状态文件，注册时不保存（守护进程只注册了部分任务）
+		if _, err := a.registerSchedule(taskID); err != nil {
+			fmt.Printf("注册任务 %s 的定时调度失败: %v\n", taskID, err)
+			continue
+		}
+		state.registered[taskID] = spec
+	}
+}
+
+// removeCronJob 从调度器中移除任务（不修改保存的调度状态）
+func (a *App) removeCronJob(taskID string) {
+	a.cronMutex.Lock()
+	defer a.cronMutex.Unlock()
+
+	if entryID, exists := a.cronJobs[taskID]; exists {
+		a.cronScheduler.Remove(entryID)
+		delete(a.cronJobs, taskID)
+	}
+}
+
+// waitForRunningTasks 等待执行中的任务完成，超时或再次收到信号时停止它们，返回被中断的任务ID
+func (a *App) waitForRunningTasks(grace time.Duration, signals <-chan os.Signal) []string {
+	deadline := time.NewTimer(grace)
+	defer deadline.Stop()
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+
+	for a.hasRunningTasks() {
+		select {
+		case <-ticker.C:
+		case <-deadline.C:
+			return a.interruptRunningTasks()
+		case <-signals:
+			return a.interruptRunningTasks()
+		}
+	}
+	return nil
+}
+
+// interruptRunningTasks 停止执行中的任务并等待它们记录结果后退出
+func (a *App) interruptRunningTasks() []string {
+	a.taskMutex.Lock()
+	taskIDs := make([]string, 0, len(a.runningTasks))
+	var runs []*TaskProgress
+	for taskID, taskRuns := range a.runningTasks {
+		taskIDs = append(taskIDs, taskID)
+		runs = append(runs, taskRuns...)
+	}
+	// 执行协程收到停止信号后记录"已停止"并清理运行状态
+	for _, run := range runs {
+		run.cancel()
+	}
+	queued := a.queuedRuns
+	a.queuedRuns = make(map[string]int64)
+	a.taskMutex.Unlock()
+
+	for _, fireID := range queued {
+		a.finishScheduleFire(fireID, "", "stopped")
+	}
+
+	for _, run := range runs {
+		<-run.done
+	}
+	return taskIDs
+}
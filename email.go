@@ -0,0 +1,345 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 邮件发送超时
+const (
+	smtpDialTimeout = 15 * time.Second
+	smtpSendTimeout = 60 * time.Second
+)
+
+// EmailSettings 邮件通知渠道的SMTP配置
+type EmailSettings struct {
+	Host         string   `json:"host"`
+	Port         int      `json:"port"`
+	Security     string   `json:"security"` // starttls, tls, none
+	Username     string   `json:"username"` // 为空时不认证
+	Password     string   `json:"password"`
+	SkipVerify   bool     `json:"skipVerify"` // 跳过证书校验（自签名证书）
+	From         string   `json:"from"`
+	To           []string `json:"to"`
+	Attachment   string   `json:"attachment"`   // 附带执行结果：csv, junit（为空时不附带）
+	LinkTemplate string   `json:"linkTemplate"` // 结果链接模板，支持 {{taskId}}、{{taskLogId}}（为空时不显示）
+}
+
+// validate 校验SMTP配置
+func (e *EmailSettings) validate() error {
+	if e == nil {
+		return fmt.Errorf("未配置SMTP")
+	}
+	if e.Host == "" {
+		return fmt.Errorf("SMTP服务器不能为空")
+	}
+	if e.Port <= 0 || e.Port > 65535 {
+		return fmt.Errorf("SMTP端口必须在1-65535之间")
+	}
+	switch e.Security {
+	case "starttls", "tls", "none":
+	default:
+		return fmt.Errorf("不支持的加密方式：%s", e.Security)
+	}
+	if e.From == "" || len(e.To) == 0 {
+		return fmt.Errorf("发件人和收件人不能为空")
+	}
+	if _, err := parseEmailAddress(e.From); err != nil {
+		return fmt.Errorf("发件人%v", err)
+	}
+	for _, to := range e.To {
+		if _, err := parseEmailAddress(to); err != nil {
+			return fmt.Errorf("收件人%v", err)
+		}
+	}
+	switch e.Attachment {
+	case "", "csv", "junit":
+	default:
+		return fmt.Errorf("不支持的附件格式：%s", e.Attachment)
+	}
+	return nil
+}
+
+// sendEmailNotification 通过SMTP发送执行结果邮件
+func (a *App) sendEmailNotification(settings *EmailSettings, n RunNotification) error {
+	// 发送前再次校验，地址会直接写入SMTP命令和邮件头
+	if err := settings.validate(); err != nil {
+		return err
+	}
+	message, err := a.buildNotificationEmail(settings, n)
+	if err != nil {
+		return err
+	}
+
+	client, err := dialSMTP(settings)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if settings.Username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return fmt.Errorf("SMTP服务器不支持认证")
+		}
+		if err := client.Auth(smtp.PlainAuth("", settings.Username, settings.Password, settings.Host)); err != nil {
+			return fmt.Errorf("SMTP认证失败：%v", err)
+		}
+	}
+
+	if err := client.Mail(emailAddress(settings.From)); err != nil {
+		return fmt.Errorf("发件人被拒绝：%v", err)
+	}
+	for _, to := range settings.To {
+		if err := client.Rcpt(emailAddress(to)); err != nil {
+			return fmt.Errorf("收件人 %s 被拒绝：%v", to, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(message); err != nil {
+		writer.Close()
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("发送邮件失败：%v", err)
+	}
+
+	return client.Quit()
+}
+
+// dialSMTP 按加密方式连接SMTP服务器
+func dialSMTP(settings *EmailSettings) (*smtp.Client, error) {
+	address := net.JoinHostPort(settings.Host, strconv.Itoa(settings.Port))
+	tlsConfig := &tls.Config{ServerName: settings.Host, InsecureSkipVerify: settings.SkipVerify}
+
+	var conn net.Conn
+	var err error
+	if settings.Security == "tls" {
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: smtpDialTimeout}, "tcp", address, tlsConfig)
+	} else {
+		conn, err = net.DialTimeout("tcp", address, smtpDialTimeout)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("连接SMTP服务器失败：%v", err)
+	}
+	conn.SetDeadline(time.Now().Add(smtpSendTimeout))
+
+	client, err := smtp.NewClient(conn, settings.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("SMTP握手失败：%v", err)
+	}
+
+	if settings.Security == "starttls" {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			client.Close()
+			return nil, fmt.Errorf("SMTP服务器不支持STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("STARTTLS失败：%v", err)
+		}
+	}
+
+	return client, nil
+}
+
+// buildNotificationEmail 构建包含文本和HTML正文（以及可选附件）的邮件
+func (a *App) buildNotificationEmail(settings *EmailSettings, n RunNotification) ([]byte, error) {
+	link := ""
+	if settings.LinkTemplate != "" {
+		link = strings.NewReplacer("{{taskId}}", n.TaskID, "{{taskLogId}}", n.TaskLogID).Replace(settings.LinkTemplate)
+	}
+
+	text := notificationText(n, false)
+	if link != "" {
+		text += "\n执行结果: " + link
+	}
+
+	// 附件：导出的执行结果（测试通知等没有执行日志时不附带）
+	var attachmentName, attachmentType string
+	var attachment []byte
+	if settings.Attachment != "" {
+		if executionLog, err := a.getExportExecutionLog(n.TaskLogID, ""); err == nil {
+			switch settings.Attachment {
+			case "csv":
+				attachment, err = buildCSVReport(executionLog)
+				attachmentName, attachmentType = exportFileName(n.TaskLogID, "csv"), "text/csv; charset=UTF-8"
+			case "junit":
+				attachment, err = buildJUnitReport(executionLog, n.TaskName)
+				attachmentName, attachmentType = exportFileName(n.TaskLogID, "xml"), "application/xml"
+			}
+			if err != nil {
+				return nil, fmt.Errorf("生成附件失败：%v", err)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	body := multipart.NewWriter(&buf)
+
+	headers := []string{
+		"From: " + formatEmailAddresses([]string{settings.From}),
+		"To: " + formatEmailAddresses(settings.To),
+		"Subject: " + mime.QEncoding.Encode("UTF-8", fmt.Sprintf("[%s] 任务 '%s' %s", AppName, n.TaskName, n.EventText)),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"Message-ID: " + newMessageID(settings.Host),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/mixed; boundary=" + body.Boundary(),
+	}
+	header := strings.Join(headers, "\r\n") + "\r\n\r\n"
+
+	// 正文：文本和HTML两种格式
+	var alternativeBuf bytes.Buffer
+	alternative := multipart.NewWriter(&alternativeBuf)
+	if err := writeMIMEPart(alternative, "text/plain; charset=UTF-8", []byte(text)); err != nil {
+		return nil, err
+	}
+	if err := writeMIMEPart(alternative, "text/html; charset=UTF-8", []byte(notificationHTML(n, link))); err != nil {
+		return nil, err
+	}
+	alternative.Close()
+
+	part, err := body.CreatePart(textproto.MIMEHeader{"Content-Type": {"multipart/alternative; boundary=" + alternative.Boundary()}})
+	if err != nil {
+		return nil, err
+	}
+	part.Write(alternativeBuf.Bytes())
+
+	if attachment != nil {
+		part, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {attachmentType + "; name=\"" + attachmentName + "\""},
+			"Content-Disposition":       {"attachment; filename=\"" + attachmentName + "\""},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		writeBase64Lines(part, attachment)
+	}
+	body.Close()
+
+	return append([]byte(header), buf.Bytes()...), nil
+}
+
+// writeMIMEPart 写入base64编码的正文部分
+func writeMIMEPart(writer *multipart.Writer, contentType string, content []byte) error {
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return err
+	}
+	writeBase64Lines(part, content)
+	return nil
+}
+
+// writeBase64Lines 按每行76个字符写入base64内容
+func writeBase64Lines(w io.Writer, content []byte) {
+	encoded := base64.StdEncoding.EncodeToString(content)
+	for len(encoded) > 76 {
+		w.Write([]byte(encoded[:76] + "\r\n"))
+		encoded = encoded[76:]
+	}
+	w.Write([]byte(encoded + "\r\n"))
+}
+
+// notificationHTML 生成邮件的HTML正文
+func notificationHTML(n RunNotification, link string) string {
+	color := "#28a745"
+	switch n.Status {
+	case "failed":
+		color = "#dc3545"
+	case "partial":
+		color = "#fd7e14"
+	}
+
+	var b strings.Builder
+	b.WriteString(`<html><body style="font-family: sans-serif; color: #333;">`)
+	fmt.Fprintf(&b, `<h2 style="color: %s;">任务 '%s' %s</h2>`, color, html.EscapeString(n.TaskName), html.EscapeString(n.EventText))
+	b.WriteString(`<table cellpadding="6" style="border-collapse: collapse;">`)
+
+	rows := [][2]string{
+		{"结果", fmt.Sprintf("成功 %d/%d（成功率 %.1f%%）", n.SuccessCount, n.TotalRequests, n.SuccessRate)},
+		{"耗时", fmt.Sprintf("%d秒", n.Duration)},
+		{"摘要", n.Summary},
+		{"完成时间", n.FinishedAt},
+		{"执行日志ID", n.TaskLogID},
+	}
+	for _, row := range rows {
+		fmt.Fprintf(&b, `<tr><td style="color: #666;">%s</td><td>%s</td></tr>`, row[0], html.EscapeString(row[1]))
+	}
+	b.WriteString(`</table>`)
+
+	if len(n.FailureGroups) > 0 {
+		b.WriteString(`<h3>主要失败原因</h3><table cellpadding="6" style="border-collapse: collapse;">`)
+		for _, group := range n.FailureGroups {
+			fmt.Fprintf(&b, `<tr><td>%s</td><td style="text-align: right;">%d次</td></tr>`, html.EscapeString(group.Summary), group.Count)
+		}
+		b.WriteString(`</table>`)
+	}
+
+	if link != "" {
+		fmt.Fprintf(&b, `<p><a href="%s">查看执行结果</a></p>`, html.EscapeString(link))
+	}
+
+	b.WriteString(`</body></html>`)
+	return b.String()
+}
+
+// parseEmailAddress 解析 "名称 <地址>" 或 "地址" 格式的邮箱地址（拒绝包含换行的值，避免注入SMTP命令和邮件头）
+func parseEmailAddress(value string) (*mail.Address, error) {
+	if strings.ContainsAny(value, "\r\n") {
+		return nil, fmt.Errorf("地址不能包含换行：%q", value)
+	}
+	address, err := mail.ParseAddress(value)
+	if err != nil {
+		return nil, fmt.Errorf("地址无效：%s", value)
+	}
+	return address, nil
+}
+
+// emailAddress 从 "名称 <地址>" 格式中提取邮箱地址（地址已通过 validate 校验）
+func emailAddress(value string) string {
+	address, err := parseEmailAddress(value)
+	if err != nil {
+		return ""
+	}
+	return address.Address
+}
+
+// formatEmailAddresses 格式化邮件头中的地址（对非ASCII名称编码，地址已通过 validate 校验）
+func formatEmailAddresses(values []string) string {
+	formatted := make([]string, 0, len(values))
+	for _, value := range values {
+		if address, err := parseEmailAddress(value); err == nil {
+			formatted = append(formatted, address.String())
+		}
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// newMessageID 生成邮件的 Message-ID
+func newMessageID(host string) string {
+	buf := make([]byte, 12)
+	rand.Read(buf)
+	return fmt.Sprintf("<%x.%d@%s>", buf, time.Now().UnixNano(), host)
+}
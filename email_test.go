@@ -0,0 +1,249 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTPMessage 测试SMTP服务器收到的一封邮件
+type fakeSMTPMessage struct {
+	From     string
+	To       []string
+	Data     string
+	Auth     string
+	StartTLS bool
+}
+
+// startFakeSMTP 启动只处理一个连接的本地SMTP服务器，tlsConfig 不为空时支持 STARTTLS 并在加密后支持 AUTH PLAIN
+func startFakeSMTP(t *testing.T, tlsConfig *tls.Config) (int, <-chan fakeSMTPMessage) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan fakeSMTPMessage, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+		var message fakeSMTPMessage
+		text := textproto.NewConn(conn)
+		text.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			command, arg, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(command) {
+			case "EHLO":
+				extensions := []string{"250-localhost"}
+				if tlsConfig != nil && !message.StartTLS {
+					extensions = append(extensions, "250-STARTTLS")
+				}
+				if message.StartTLS {
+					extensions = append(extensions, "250-AUTH PLAIN")
+				}
+				extensions = append(extensions, "250 8BITMIME")
+				for _, extension := range extensions {
+					text.PrintfLine("%s", extension)
+				}
+			case "STARTTLS":
+				text.PrintfLine("220 ready")
+				tlsConn := tls.Server(conn, tlsConfig)
+				if err := tlsConn.Handshake(); err != nil {
+					return
+				}
+				conn = tlsConn
+				text = textproto.NewConn(conn)
+				message.StartTLS = true
+			case "AUTH":
+				credentials, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(arg, "PLAIN "))
+				message.Auth = string(credentials)
+				text.PrintfLine("235 ok")
+			case "MAIL":
+				message.From = arg
+				text.PrintfLine("250 ok")
+			case "RCPT":
+				message.To = append(message.To, arg)
+				text.PrintfLine("250 ok")
+			case "DATA":
+				text.PrintfLine("354 go ahead")
+				data, err := text.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				message.Data = string(data)
+				text.PrintfLine("250 ok")
+			case "QUIT":
+				text.PrintfLine("221 bye")
+				messages <- message
+				return
+			default:
+				text.PrintfLine("250 ok")
+			}
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port, messages
+}
+
+// selfSignedTLSConfig 生成测试用的自签名证书
+func selfSignedTLSConfig(t *testing.T) *tls.Config {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{cert}, PrivateKey: key}}}
+}
+
+// waitFakeSMTP 等待测试SMTP服务器收到邮件
+func waitFakeSMTP(t *testing.T, messages <-chan fakeSMTPMessage) fakeSMTPMessage {
+	t.Helper()
+	select {
+	case message := <-messages:
+		return message
+	case <-time.After(10 * time.Second):
+		t.Fatal("SMTP服务器未收到邮件")
+		return fakeSMTPMessage{}
+	}
+}
+
+// testNotification 测试用的执行结果通知（不附带执行日志）
+func testNotification() RunNotification {
+	return RunNotification{
+		Event:         "failure",
+		EventText:     "执行失败",
+		TaskID:        "task-1",
+		TaskName:      "测试任务",
+		TaskLogID:     "log-1",
+		Status:        "failed",
+		TotalRequests: 10,
+		SuccessCount:  8,
+		FailedCount:   2,
+	}
+}
+
+func TestSendEmailNotificationPlain(t *testing.T) {
+	port, messages := startFakeSMTP(t, nil)
+	settings := &EmailSettings{
+		Host:     "127.0.0.1",
+		Port:     port,
+		Security: "none",
+		From:     "Runner <runner@example.com>",
+		To:       []string{"ops@example.com", "张三 <zhangsan@example.com>"},
+	}
+
+	app := &App{}
+	if err := app.sendEmailNotification(settings, testNotification()); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	message := waitFakeSMTP(t, messages)
+	if message.StartTLS || message.Auth != "" {
+		t.Errorf("不应加密或认证: %+v", message)
+	}
+	if !strings.HasPrefix(message.From, "FROM:<runner@example.com>") {
+		t.Errorf("MAIL FROM = %q", message.From)
+	}
+	if len(message.To) != 2 || message.To[0] != "TO:<ops@example.com>" || message.To[1] != "TO:<zhangsan@example.com>" {
+		t.Errorf("RCPT TO = %q", message.To)
+	}
+	if !strings.Contains(message.Data, "From: \"Runner\" <runner@example.com>\n") {
+		t.Errorf("邮件头缺少发件人:\n%s", message.Data)
+	}
+	if !strings.Contains(message.Data, "To: <ops@example.com>, =?utf-8?q?") {
+		t.Errorf("邮件头缺少收件人:\n%s", message.Data)
+	}
+}
+
+func TestSendEmailNotificationStartTLS(t *testing.T) {
+	port, messages := startFakeSMTP(t, selfSignedTLSConfig(t))
+	settings := &EmailSettings{
+		Host:       "127.0.0.1",
+		Port:       port,
+		Security:   "starttls",
+		Username:   "user",
+		Password:   "secret",
+		SkipVerify: true,
+		From:       "runner@example.com",
+		To:         []string{"ops@example.com"},
+	}
+
+	app := &App{}
+	if err := app.sendEmailNotification(settings, testNotification()); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	message := waitFakeSMTP(t, messages)
+	if !message.StartTLS {
+		t.Error("未使用STARTTLS")
+	}
+	if message.Auth != "\x00user\x00secret" {
+		t.Errorf("AUTH PLAIN = %q", message.Auth)
+	}
+	if len(message.To) != 1 || message.To[0] != "TO:<ops@example.com>" {
+		t.Errorf("RCPT TO = %q", message.To)
+	}
+	if !strings.Contains(message.Data, "Subject: ") {
+		t.Errorf("邮件缺少主题:\n%s", message.Data)
+	}
+}
+
+func TestEmailSettingsValidateAddresses(t *testing.T) {
+	valid := EmailSettings{Host: "smtp.example.com", Port: 25, Security: "none", From: "runner@example.com", To: []string{"ops@example.com"}}
+	if err := valid.validate(); err != nil {
+		t.Fatalf("有效配置校验失败: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		from string
+		to   []string
+	}{
+		{"发件人换行注入", "runner@example.com\r\nBcc: evil@example.com", []string{"ops@example.com"}},
+		{"收件人换行注入", "runner@example.com", []string{"ops@example.com\nRCPT TO:<evil@example.com>"}},
+		{"发件人格式无效", "not an address", []string{"ops@example.com"}},
+		{"收件人格式无效", "runner@example.com", []string{"ops@example.com", "ops@"}},
+	}
+	for _, tt := range tests {
+		settings := valid
+		settings.From, settings.To = tt.from, tt.to
+		if err := settings.validate(); err == nil {
+			t.Errorf("%s: 应校验失败", tt.name)
+		}
+		app := &App{}
+		if err := app.sendEmailNotification(&settings, testNotification()); err == nil {
+			t.Errorf("%s: 应拒绝发送", tt.name)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// 推送给前端的事件名称
+const (
+	EventTaskStarted   = "task:started"   // 任务开始执行
+	EventTaskProgress  = "task:progress"  // 执行进度（节流）
+	EventTaskRequests  = "task:requests"  // 请求完成（与进度事件一起按批推送）
+	EventTaskFinished  = "task:finished"  // 任务执行结束
+	EventScheduleFired = "schedule:fired" // 定时任务触发
+)
+
+// progressEventPeriod 进度事件和请求事件的最小推送间隔
+const progressEventPeriod = 200 * time.Millisecond
+
+// TaskStartedEvent 任务开始执行事件
+type TaskStartedEvent struct {
+	TaskID    string `json:"taskId"`
+	TaskName  string `json:"taskName"`
+	Trigger   string `json:"trigger"` // manual, schedule, api, cli, chained
+	Total     int    `json:"total"`
+	StartTime int64  `json:"startTime"`
+}
+
+// TaskProgressEvent 执行进度事件
+type TaskProgressEvent struct {
+	TaskID       string `json:"taskId"`
+	Current      int    `json:"current"`
+	Total        int    `json:"total"`
+	SuccessCount int    `json:"successCount"`
+	FailedCount  int    `json:"failedCount"`
+}
+
+// TaskRequestsEvent 一批请求完成事件
+type TaskRequestsEvent struct {
+	TaskID   string             `json:"taskId"`
+	Requests []TaskRequestEvent `json:"requests"` // 按完成顺序排列
+}
+
+// TaskRequestEvent 单个请求的结果摘要（不包含响应内容）
+type TaskRequestEvent struct {
+	RequestID     string `json:"requestId"`
+	Timestamp     string `json:"timestamp"`
+	URL           string `json:"url"`
+	Method        string `json:"method"`
+	StatusCode    int    `json:"statusCode"`
+	ResponseTime  int64  `json:"responseTime"`
+	Success       bool   `json:"success"`
+	ErrorType     string `json:"errorType"`
+	DetailedError string `json:"detailedError"`
+}
+
+// TaskFinishedEvent 任务执行结束事件
+type TaskFinishedEvent struct {
+	TaskID       string `json:"taskId"`
+	TaskLogID    string `json:"taskLogId"`
+	Trigger      string `json:"trigger"`
+	Status       string `json:"status"` // success, partial, failed
+	Total        int    `json:"total"`
+	SuccessCount int    `json:"successCount"`
+	FailedCount  int    `json:"failedCount"`
+	Duration     int64  `json:"duration"`
+	Summary      string `json:"summary"`
+}
+
+// ScheduleFiredEvent 定时任务触发事件
+type ScheduleFiredEvent struct {
+	TaskID   string `json:"taskId"`
+	TaskName string `json:"taskName"`
+	FiredAt  string `json:"firedAt"`
+	Action   string `json:"action"` // run, queued, skipped, replaced（见 applyOverlapPolicy）, inactive（不在生效时间范围内）
+}
+
+// emitEvent 向前端推送事件（命令行和守护进程模式没有窗口，直接忽略）
+func (a *App) emitEvent(name string, data interface{}) {
+	if a.ctx == nil {
+		return
+	}
+	runtime.EventsEmit(a.ctx, name, data)
+}
+
+// progressEmitter 对进度事件和请求事件节流，避免大量请求时频繁推送
+type progressEmitter struct {
+	app      *App
+	taskID   string
+	total    int
+	lastSent time.Time
+
+	mutex    sync.Mutex         // 保护 requests（工作协程并发添加）
+	requests []TaskRequestEvent // 等待推送的请求
+}
+
+// addRequest 记录完成的请求，随下一次进度事件一起推送
+func (p *progressEmitter) addRequest(detailLog DetailedLogEntry) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.requests = append(p.requests, TaskRequestEvent{
+		RequestID:     detailLog.RequestID,
+		Timestamp:     detailLog.Timestamp,
+		URL:           detailLog.URL,
+		Method:        detailLog.Method,
+		StatusCode:    detailLog.StatusCode,
+		ResponseTime:  detailLog.ResponseTime,
+		Success:       detailLog.Success,
+		ErrorType:     detailLog.ErrorType,
+		DetailedError: detailLog.DetailedError,
+	})
+}
+
+// flushRequests 推送等待中的请求
+func (p *progressEmitter) flushRequests() {
+	p.mutex.Lock()
+	requests := p.requests
+	p.requests = nil
+	p.mutex.Unlock()
+
+	if len(requests) > 0 {
+		p.app.emitEvent(EventTaskRequests, TaskRequestsEvent{TaskID: p.taskID, Requests: requests})
+	}
+}
+
+// update 推送最新进度和期间完成的请求（距上次推送不足 progressEventPeriod 时跳过，最后一次总是推送）
+func (p *progressEmitter) update(current, successCount int) {
+	if current < p.total && time.Since(p.lastSent) < progressEventPeriod {
+		return
+	}
+	p.lastSent = time.Now()
+
+	p.flushRequests()
+	p.app.emitEvent(EventTaskProgress, TaskProgressEvent{
+		TaskID:       p.taskID,
+		Current:      current,
+		Total:        p.total,
+		SuccessCount: successCount,
+		FailedCount:  current - successCount,
+	})
+}
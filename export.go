@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// LogExportFilter 导出详细日志时的筛选条件
+type LogExportFilter struct {
+	Status     string   `json:"status"`     // all, success, failed
+	ErrorType  string   `json:"errorType"`  // 仅导出指定错误类型: network, parsing, condition, http
+	RequestIDs []string `json:"requestIds"` // 仅导出指定请求ID
+}
+
+// parseLogExportFilter 解析筛选条件JSON，为空时导出全部
+func parseLogExportFilter(filterJson string) (LogExportFilter, error) {
+	var filter LogExportFilter
+	if filterJson == "" {
+		return filter, nil
+	}
+	if err := json.Unmarshal([]byte(filterJson), &filter); err != nil {
+		return filter, fmt.Errorf("筛选条件格式错误：%v", err)
+	}
+	return filter, nil
+}
+
+// apply 按筛选条件过滤详细日志
+func (f LogExportFilter) apply(entries []DetailedLogEntry) []DetailedLogEntry {
+	requestIDs := make(map[string]bool, len(f.RequestIDs))
+	for _, id := range f.RequestIDs {
+		requestIDs[id] = true
+	}
+
+	result := make([]DetailedLogEntry, 0, len(entries))
+	for _, entry := range entries {
+		if f.Status == "success" && !entry.Success {
+			continue
+		}
+		if f.Status == "failed" && entry.Success {
+			continue
+		}
+		if f.ErrorType != "" && entry.ErrorType != f.ErrorType {
+			continue
+		}
+		if len(requestIDs) > 0 && !requestIDs[entry.RequestID] {
+			continue
+		}
+		result = append(result, entry)
+	}
+
+	return result
+}
+
+// getExportExecutionLog 获取要导出的执行日志（按请求时间正序）
+func (a *App) getExportExecutionLog(taskLogID, filterJson string) (*ExecutionLog, error) {
+	filter, err := parseLogExportFilter(filterJson)
+	if err != nil {
+		return nil, err
+	}
+
+	executionLog := a.GetExecutionLog(taskLogID)
+	if executionLog == nil {
+		return nil, fmt.Errorf("执行日志不存在")
+	}
+
+	// 导出时按发生顺序排列（时间戳只精确到秒，同一秒内按包含纳秒时间戳的请求ID排序，保证每次导出顺序一致）
+	entries := filter.apply(executionLog.DetailedLogs)
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Timestamp != entries[j].Timestamp {
+			return entries[i].Timestamp < entries[j].Timestamp
+		}
+		return entries[i].RequestID < entries[j].RequestID
+	})
+	executionLog.DetailedLogs = entries
+
+	return executionLog, nil
+}
+
+// resolveExportPath 确定导出文件路径，path 为空时弹出保存对话框让用户选择
+func (a *App) resolveExportPath(path, defaultFilename, filterName, pattern string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+
+	if a.ctx == nil {
+		return "", fmt.Errorf("未指定导出路径")
+	}
+
+	selected, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:                "选择导出位置",
+		DefaultFilename:      defaultFilename,
+		CanCreateDirectories: true,
+		Filters: []runtime.FileFilter{
+			{DisplayName: filterName, Pattern: pattern},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("打开保存对话框失败：%v", err)
+	}
+	if selected == "" {
+		return "", fmt.Errorf("已取消导出")
+	}
+
+	return selected, nil
+}
+
+// writeExportFile 写入导出文件
+func writeExportFile(path string, data []byte) error {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// exportFileName 生成默认导出文件名
+func exportFileName(taskLogID, ext string) string {
+	return fmt.Sprintf("%s.%s", taskLogID, ext)
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 每个失败分组保留的示例请求ID数量
+const maxFailureSampleIDs = 5
+
+// FailureGroup 失败分组（按错误类型、状态码、归一化错误信息和条件实际值聚合）
+type FailureGroup struct {
+	Key              string   `json:"key"`              // 分组键
+	Summary          string   `json:"summary"`          // 分组摘要（用于显示）
+	ErrorType        string   `json:"errorType"`        // 错误类型: network, parsing, condition, http
+	StatusCode       int      `json:"statusCode"`       // 响应状态码
+	Message          string   `json:"message"`          // 归一化后的错误信息
+	ActualValue      string   `json:"actualValue"`      // 成功条件实际值
+	Count            int      `json:"count"`            // 失败次数
+	FirstOccurrence  string   `json:"firstOccurrence"`  // 首次出现时间
+	LastOccurrence   string   `json:"lastOccurrence"`   // 最后出现时间
+	SampleRequestIDs []string `json:"sampleRequestIds"` // 示例请求ID
+	TaskLogIDs       []string `json:"taskLogIds"`       // 出现过该失败的执行日志ID
+}
+
+// FailureAggregation 失败聚合结果
+type FailureAggregation struct {
+	TaskID        string         `json:"taskId"`        // 任务ID（按执行聚合时可能为空）
+	TaskLogID     string         `json:"taskLogId"`     // 执行日志ID（按时间范围聚合时为空）
+	StartTime     string         `json:"startTime"`     // 时间范围开始
+	EndTime       string         `json:"endTime"`       // 时间范围结束
+	RunCount      int            `json:"runCount"`      // 参与聚合的执行次数
+	TotalRequests int            `json:"totalRequests"` // 总请求数
+	FailedCount   int            `json:"failedCount"`   // 失败请求数
+	Groups        []FailureGroup `json:"groups"`        // 失败分组（按次数倒序）
+	Error         string         `json:"error"`         // 错误信息
+}
+
+var (
+	failureURLPattern    = regexp.MustCompile(`"?[a-zA-Z][a-zA-Z0-9+.-]*://[^\s"]+"?`)
+	failureAddrPattern   = regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b|\[[0-9a-fA-F:]+\](:\d+)?`)
+	failureUUIDPattern   = regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`)
+	failureHexPattern    = regexp.MustCompile(`\b(0x)?[0-9a-fA-F]*\d[0-9a-fA-F]*[a-fA-F][0-9a-fA-F]*\b|\b(0x)?[0-9a-fA-F]*[a-fA-F][0-9a-fA-F]*\d[0-9a-fA-F]*\b`)
+	failureNumberPattern = regexp.MustCompile(`\d+(\.\d+)?`)
+	failureSpacePattern  = regexp.MustCompile(`\s+`)
+)
+
+// GetRunFailureGroups 获取单次执行的失败聚合
+func (a *App) GetRunFailureGroups(taskLogID string) FailureAggregation {
+	a.logMutex.RLock()
+	defer a.logMutex.RUnlock()
+
+	result := FailureAggregation{TaskLogID: taskLogID, Groups: []FailureGroup{}}
+
+	executionLog, exists := a.executionLogs[taskLogID]
+	if !exists {
+		result.Error = "执行日志不存在"
+		return result
+	}
+
+	result.TaskID = a.findTaskIDByLogIDLocked(taskLogID)
+	groups := make(map[string]*FailureGroup)
+	a.aggregateExecutionFailures(&result, groups, taskLogID, executionLog)
+	result.Groups = sortFailureGroups(groups)
+
+	return result
+}
+
+// GetTaskFailureGroups 获取时间范围内的失败聚合
+// taskID 为空或 "all" 时聚合所有任务；startTime/endTime 格式为 "2006-01-02 15:04:05"，为空表示不限制
+func (a *App) GetTaskFailureGroups(taskID, startTime, endTime string) FailureAggregation {
+	result := FailureAggregation{
+		TaskID:    taskID,
+		StartTime: startTime,
+		EndTime:   endTime,
+		Groups:    []FailureGroup{},
+	}
+
+	for _, value := range []string{startTime, endTime} {
+		if value == "" {
+			continue
+		}
+		if _, err := time.ParseInLocation("2006-01-02 15:04:05", value, time.Local); err != nil {
+			result.Error = fmt.Sprintf("时间格式错误：%s（期望格式 2006-01-02 15:04:05）", value)
+			return result
+		}
+	}
+
+	a.logMutex.RLock()
+	defer a.logMutex.RUnlock()
+
+	// 遍历详细日志而不是任务日志：任务日志按保留条数截断，范围内较早的执行可能已不在其中
+	groups := make(map[string]*FailureGroup)
+	for taskLogID, executionLog := range a.executionLogs {
+		logTaskID, finishedAt, ok := parseTaskLogID(taskLogID)
+		if !ok {
+			continue
+		}
+		if taskID != "" && taskID != "all" && logTaskID != taskID {
+			continue
+		}
+
+		// 时间戳格式固定，可直接按字符串比较
+		timestamp := finishedAt.Format("2006-01-02 15:04:05")
+		if startTime != "" && timestamp < startTime {
+			continue
+		}
+		if endTime != "" && timestamp > endTime {
+			continue
+		}
+
+		a.aggregateExecutionFailures(&result, groups, taskLogID, executionLog)
+	}
+
+	result.Groups = sortFailureGroups(groups)
+	return result
+}
+
+// aggregateExecutionFailures 将一次执行的失败请求合并到分组中（调用方需持有logMutex）
+func (a *App) aggregateExecutionFailures(result *FailureAggregation, groups map[string]*FailureGroup, taskLogID string, executionLog ExecutionLog) {
+	result.RunCount++
+	result.TotalRequests += executionLog.TotalRequests
+
+	for _, entry := range executionLog.DetailedLogs {
+		if entry.Success {
+			continue
+		}
+		result.FailedCount++
+
+		actualValue := ""
+		if entry.ErrorType == "condition" && entry.SuccessConditionDetails != nil {
+			actualValue = entry.SuccessConditionDetails.ActualValue
+		}
+		message := normalizeFailureMessage(entry.Error)
+		key := fmt.Sprintf("%s|%d|%s|%s", entry.ErrorType, entry.StatusCode, message, actualValue)
+
+		group, exists := groups[key]
+		if !exists {
+			group = &FailureGroup{
+				Key:              key,
+				Summary:          a.describeFailureGroup(entry, message, actualValue),
+				ErrorType:        entry.ErrorType,
+				StatusCode:       entry.StatusCode,
+				Message:          message,
+				ActualValue:      actualValue,
+				FirstOccurrence:  entry.Timestamp,
+				LastOccurrence:   entry.Timestamp,
+				SampleRequestIDs: []string{},
+				TaskLogIDs:       []string{},
+			}
+			groups[key] = group
+		}
+
+		group.Count++
+		if entry.Timestamp < group.FirstOccurrence {
+			group.FirstOccurrence = entry.Timestamp
+		}
+		if entry.Timestamp > group.LastOccurrence {
+			group.LastOccurrence = entry.Timestamp
+		}
+		if len(group.SampleRequestIDs) < maxFailureSampleIDs {
+			group.SampleRequestIDs = append(group.SampleRequestIDs, entry.RequestID)
+		}
+		if len(group.TaskLogIDs) == 0 || group.TaskLogIDs[len(group.TaskLogIDs)-1] != taskLogID {
+			group.TaskLogIDs = append(group.TaskLogIDs, taskLogID)
+		}
+	}
+}
+
+// describeFailureGroup 生成失败分组的简短描述
+func (a *App) describeFailureGroup(entry DetailedLogEntry, message, actualValue string) string {
+	switch entry.ErrorType {
+	case "network":
+		return "网络连接失败: " + message
+	case "parsing":
+		return "响应解析失败: " + message
+	case "condition":
+		if entry.SuccessConditionDetails != nil && entry.SuccessConditionDetails.JsonPath != "" {
+			return fmt.Sprintf("成功条件不满足 (%s = \"%s\")", entry.SuccessConditionDetails.JsonPath, actualValue)
+		}
+		if actualValue != "" {
+			return fmt.Sprintf("成功条件不满足 (实际值: \"%s\")", actualValue)
+		}
+		return "成功条件不满足"
+	case "http":
+		return fmt.Sprintf("HTTP %d 错误", entry.StatusCode)
+	default:
+		if message != "" {
+			return message
+		}
+		return "请求失败"
+	}
+}
+
+// normalizeFailureMessage 归一化错误信息，去除URL、地址、ID和数字等易变部分
+func normalizeFailureMessage(message string) string {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return ""
+	}
+
+	message = failureURLPattern.ReplaceAllString(message, "<url>")
+	message = failureAddrPattern.ReplaceAllString(message, "<addr>")
+	message = failureUUIDPattern.ReplaceAllString(message, "<uuid>")
+	message = failureHexPattern.ReplaceAllString(message, "<hex>")
+	message = failureNumberPattern.ReplaceAllString(message, "<n>")
+	message = failureSpacePattern.ReplaceAllString(message, " ")
+
+	return message
+}
+
+// sortFailureGroups 将分组按失败次数倒序排列
+func sortFailureGroups(groups map[string]*FailureGroup) []FailureGroup {
+	result := make([]FailureGroup, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Key < result[j].Key
+	})
+
+	return result
+}
+
+// parseTaskLogID 从执行日志ID（任务ID_纳秒时间戳）中解析任务ID和执行结束时间
+func parseTaskLogID(taskLogID string) (string, time.Time, bool) {
+	index := strings.LastIndex(taskLogID, "_")
+	if index <= 0 {
+		return "", time.Time{}, false
+	}
+	nanos, err := strconv.ParseInt(taskLogID[index+1:], 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return taskLogID[:index], time.Unix(0, nanos), true
+}
+
+// findTaskIDByLogIDLocked 根据执行日志ID查找所属任务（调用方需持有logMutex）
+func (a *App) findTaskIDByLogIDLocked(taskLogID string) string {
+	for taskID, logs := range a.taskLogs {
+		for _, entry := range logs {
+			if entry.ID == taskLogID {
+				return taskID
+			}
+		}
+	}
+	return ""
+}
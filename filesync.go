@@ -0,0 +1,397 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// 桌面程序、守护进程和命令行共用任务文件和日志文件，写入前持有跨进程文件锁并合并其他进程的修改
+// 锁文件超过 fileLockStaleAfter 未更新视为持有锁的进程已退出
+const (
+	fileLockTimeout    = 10 * time.Second
+	fileLockStaleAfter = 30 * time.Second
+)
+
+// withFileLock 持有数据文件的跨进程锁执行 fn（通过独占创建 path.lock 实现，兼容各平台）
+// 锁文件中写入持有者的标识，释放和清理过期锁时只删除标识匹配的锁文件；
+// fn 执行期间定期更新锁文件的修改时间，执行时间较长时也不会被其他进程视为过期
+func withFileLock(path string, fn func() error) error {
+	lockPath := path + ".lock"
+	token := newFileLockToken()
+	deadline := time.Now().Add(fileLockTimeout)
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, err = file.WriteString(token)
+			file.Close()
+			if err != nil {
+				os.Remove(lockPath)
+				return fmt.Errorf("写入锁文件失败：%v", err)
+			}
+			break
+		}
+		if !os.IsExist(err) {
+			return fmt.Errorf("创建锁文件失败：%v", err)
+		}
+		if removeStaleFileLock(lockPath) {
+			continue
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("等待文件锁超时：%s", lockPath)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	defer releaseFileLock(lockPath, token)
+
+	done := make(chan struct{})
+	defer close(done)
+	go refreshFileLock(lockPath, done)
+
+	return fn()
+}
+
+// newFileLockToken 生成锁持有者的标识（进程ID加随机数）
+func newFileLockToken() string {
+	nonce := make([]byte, 8)
+	rand.Read(nonce)
+	return fmt.Sprintf("%d-%x", os.Getpid(), nonce)
+}
+
+// refreshFileLock 定期更新锁文件的修改时间，直到 done 关闭
+func refreshFileLock(lockPath string, done <-chan struct{}) {
+	ticker := time.NewTicker(fileLockStaleAfter / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			now := time.Now()
+			os.Chtimes(lockPath, now, now)
+		}
+	}
+}
+
+// releaseFileLock 释放锁（锁文件已不属于 token 时保留）
+func releaseFileLock(lockPath, token string) {
+	if owner, err := os.ReadFile(lockPath); err == nil && string(owner) == token {
+		os.Remove(lockPath)
+	}
+}
+
+// removeStaleFileLock 删除超过 fileLockStaleAfter 未更新的锁文件（持有锁的进程已退出），返回是否已删除
+// 删除前确认锁文件仍是判断为过期的那个，避免删除其他进程刚取得的锁
+func removeStaleFileLock(lockPath string) bool {
+	owner, err := os.ReadFile(lockPath)
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(lockPath)
+	if err != nil || time.Since(info.ModTime()) <= fileLockStaleAfter {
+		return false
+	}
+	if current, err := os.ReadFile(lockPath); err != nil || !bytes.Equal(current, owner) {
+		return false
+	}
+	return os.Remove(lockPath) == nil
+}
+
+// writeFileAtomic 先写临时文件再重命名，避免其他进程读取到不完整的内容
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// fileUnchanged 判断文件自上次同步后是否未变化（写入通过重命名替换文件，修改时间精度不足时也能识别）
+func fileUnchanged(last, current os.FileInfo) bool {
+	return last != nil && os.SameFile(last, current) && last.ModTime().Equal(current.ModTime()) && last.Size() == current.Size()
+}
+
+// readJSONFile 读取JSON文件，文件不存在时返回 false
+func readJSONFile(path string, v interface{}) (bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("解析 %s 失败：%v", path, err)
+	}
+	return true, nil
+}
+
+// decodeTasks 解析任务文件内容（内容为空或无效时返回空集合）
+func decodeTasks(data []byte) map[string]*Task {
+	tasks := make(map[string]*Task)
+	if len(data) > 0 {
+		json.Unmarshal(data, &tasks)
+	}
+	return tasks
+}
+
+// syncTasksWithDisk 读取任务文件并合并到缓存，save 为 true 时写回合并结果（调用方需持有cacheMutex写锁）
+func (a *App) syncTasksWithDisk(save bool) error {
+	path := a.getTasksPath()
+	return withFileLock(path, func() error {
+		data, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err == nil {
+			var theirs map[string]*Task
+			if err := json.Unmarshal(data, &theirs); err != nil {
+				return fmt.Errorf("解析任务文件失败：%v", err)
+			}
+			a.tasksCache = mergeTasks(decodeTasks(a.tasksSnapshot), a.tasksCache, theirs)
+			a.tasksSnapshot = data
+		}
+		if !save {
+			return nil
+		}
+
+		data, err = json.MarshalIndent(a.tasksCache, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := writeFileAtomic(path, data); err != nil {
+			return err
+		}
+		a.tasksSnapshot = data
+		return nil
+	})
+}
+
+// mergeTasks 三方合并任务：base 为上次与磁盘同步时的任务，mine 为本进程的任务，theirs 为磁盘上的任务
+// 任一方删除的任务被删除，新建的任务保留，双方都存在的任务按 mergeTask 合并
+func mergeTasks(base, mine, theirs map[string]*Task) map[string]*Task {
+	merged := make(map[string]*Task, len(theirs))
+	for id, task := range theirs {
+		baseTask, inBase := base[id]
+		mineTask, inMine := mine[id]
+		switch {
+		case !inMine && inBase:
+			// 本进程已删除
+		case !inMine:
+			merged[id] = task
+		case !inBase:
+			merged[id] = mineTask
+		default:
+			merged[id] = mergeTask(baseTask, mineTask, task)
+		}
+	}
+	for id, task := range mine {
+		if _, exists := theirs[id]; exists {
+			continue
+		}
+		if _, inBase := base[id]; inBase {
+			// 其他进程已删除
+			continue
+		}
+		merged[id] = task
+	}
+	return merged
+}
+
+// taskRunState 任务的运行状态（执行时更新，与任务定义分开合并）
+type taskRunState struct {
+	IsRunning     bool
+	LastRunTime   string
+	LastRunStatus string
+	LastRunResult string
+}
+
+// runStateOf 获取任务的运行状态
+func runStateOf(task *Task) taskRunState {
+	return taskRunState{task.IsRunning, task.LastRunTime, task.LastRunStatus, task.LastRunResult}
+}
+
+// setRunState 设置任务的运行状态
+func (t *Task) setRunState(state taskRunState) {
+	t.IsRunning = state.IsRunning
+	t.LastRunTime = state.LastRunTime
+	t.LastRunStatus = state.LastRunStatus
+	t.LastRunResult = state.LastRunResult
+}
+
+// taskDefinition 任务定义的序列化结果（不含运行状态），用于判断定义是否被修改
+func taskDefinition(task *Task) string {
+	definition := *task
+	definition.setRunState(taskRunState{})
+	data, _ := json.Marshal(definition)
+	return string(data)
+}
+
+// mergeTask 合并双方都存在的任务：任务定义和运行状态分别取本进程修改过的版本，否则取磁盘上的版本
+// 这样守护进程更新执行结果时不会覆盖桌面程序对任务的编辑
+func mergeTask(base, mine, theirs *Task) *Task {
+	mineDefinition := taskDefinition(mine)
+	useMineDefinition := mineDefinition != taskDefinition(base) || mineDefinition == taskDefinition(theirs)
+	runState := runStateOf(theirs)
+	if runStateOf(mine) != runStateOf(base) {
+		runState = runStateOf(mine)
+	}
+
+	if useMineDefinition {
+		// 保留本进程的任务对象，执行中的运行仍引用它
+		mine.setRunState(runState)
+		return mine
+	}
+	merged := *theirs
+	merged.setRunState(runState)
+	return &merged
+}
+
+// syncLogsWithDisk 合并其他进程（守护进程、命令行）写入的日志
+func (a *App) syncLogsWithDisk() {
+	a.logFileMutex.Lock()
+	defer a.logFileMutex.Unlock()
+
+	taskLogsPath := a.getTaskLogsPath()
+	if err := withFileLock(taskLogsPath, func() error { return a.mergeTaskLogsFile(taskLogsPath) }); err != nil {
+		fmt.Printf("读取任务日志失败: %v\n", err)
+	}
+	executionLogsPath := a.getExecutionLogsPath()
+	if err := withFileLock(executionLogsPath, func() error { return a.mergeExecutionLogsFile(executionLogsPath) }); err != nil {
+		fmt.Printf("读取详细日志失败: %v\n", err)
+	}
+}
+
+// mergeTaskLogsFile 读取任务日志文件并合并到内存（调用方需持有logFileMutex和文件锁）
+func (a *App) mergeTaskLogsFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil || fileUnchanged(a.taskLogsFile, info) {
+		// 文件不存在或自上次同步后未变化
+		return nil
+	}
+
+	var theirs map[string][]TaskLogEntry
+	exists, err := readJSONFile(path, &theirs)
+	if err != nil || !exists {
+		return err
+	}
+
+	a.logMutex.Lock()
+	mergeTaskLogs(a.taskLogsSnapshot, a.taskLogs, theirs)
+	a.taskLogsSnapshot = taskLogIDs(theirs)
+	a.logMutex.Unlock()
+
+	a.taskLogsFile = info
+	return nil
+}
+
+// mergeExecutionLogsFile 读取详细日志文件并合并到内存（调用方需持有logFileMutex和文件锁）
+func (a *App) mergeExecutionLogsFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil || fileUnchanged(a.executionLogsFile, info) {
+		return nil
+	}
+
+	var theirs map[string]ExecutionLog
+	exists, err := readJSONFile(path, &theirs)
+	if err != nil || !exists {
+		return err
+	}
+
+	a.logMutex.Lock()
+	mergeExecutionLogs(a.executionLogsSnapshot, a.executionLogs, theirs)
+	a.executionLogsSnapshot = executionLogIDs(theirs)
+	a.logMutex.Unlock()
+
+	a.executionLogsFile = info
+	return nil
+}
+
+// mergeTaskLogs 将磁盘上的任务日志合并到 mine：base 为上次同步时磁盘上的日志ID
+// 只在一方存在的日志，若上次同步时存在说明已被该方删除，否则是该方新写入的
+func mergeTaskLogs(base map[string]bool, mine, theirs map[string][]TaskLogEntry) {
+	taskIDs := make(map[string]bool, len(mine)+len(theirs))
+	for taskID := range mine {
+		taskIDs[taskID] = true
+	}
+	for taskID := range theirs {
+		taskIDs[taskID] = true
+	}
+
+	for taskID := range taskIDs {
+		theirIDs := make(map[string]bool, len(theirs[taskID]))
+		for _, entry := range theirs[taskID] {
+			theirIDs[entry.ID] = true
+		}
+
+		merged := make([]TaskLogEntry, 0, len(mine[taskID]))
+		mineIDs := make(map[string]bool, len(mine[taskID]))
+		for _, entry := range mine[taskID] {
+			mineIDs[entry.ID] = true
+			if base[entry.ID] && !theirIDs[entry.ID] {
+				continue
+			}
+			merged = append(merged, entry)
+		}
+		added := false
+		for _, entry := range theirs[taskID] {
+			if mineIDs[entry.ID] || base[entry.ID] {
+				continue
+			}
+			merged = append(merged, entry)
+			added = true
+		}
+		if added {
+			sort.SliceStable(merged, func(i, j int) bool {
+				if merged[i].Timestamp != merged[j].Timestamp {
+					return merged[i].Timestamp < merged[j].Timestamp
+				}
+				return merged[i].ID < merged[j].ID
+			})
+		}
+
+		if len(merged) == 0 {
+			delete(mine, taskID)
+		} else {
+			mine[taskID] = merged
+		}
+	}
+}
+
+// mergeExecutionLogs 将磁盘上的详细日志合并到 mine，规则同 mergeTaskLogs
+func mergeExecutionLogs(base map[string]bool, mine, theirs map[string]ExecutionLog) {
+	for id := range mine {
+		if _, exists := theirs[id]; !exists && base[id] {
+			delete(mine, id)
+		}
+	}
+	for id, log := range theirs {
+		if _, exists := mine[id]; !exists && !base[id] {
+			mine[id] = log
+		}
+	}
+}
+
+// taskLogIDs 获取任务日志的ID集合
+func taskLogIDs(taskLogs map[string][]TaskLogEntry) map[string]bool {
+	ids := make(map[string]bool)
+	for _, logs := range taskLogs {
+		for _, entry := range logs {
+			ids[entry.ID] = true
+		}
+	}
+	return ids
+}
+
+// executionLogIDs 获取详细日志的ID集合
+func executionLogIDs(executionLogs map[string]ExecutionLog) map[string]bool {
+	ids := make(map[string]bool, len(executionLogs))
+	for id := range executionLogs {
+		ids[id] = true
+	}
+	return ids
+}
@@ -10,16 +10,48 @@ export function DeleteTask(arg1:string):Promise<string>;
 
 export function ExecuteTask(arg1:string):Promise<string>;
 
+export function ExportExecutionLogCSV(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function ExportExecutionLogHAR(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function ExportExecutionLogJUnit(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function ExportTaskAsCurl(arg1:string,arg2:boolean):Promise<string>;
+
+export function ExportTasksAsHTTPFile(arg1:Array<string>,arg2:boolean,arg3:string):Promise<string>;
+
+export function ExportWorkspace(arg1:string,arg2:string):Promise<string>;
+
+export function GetAPIStatus():Promise<main.APIStatus>;
+
+export function GetDaemonStatus():Promise<main.DaemonStatus>;
+
 export function GetEnvVariables():Promise<Record<string, string>>;
 
 export function GetEnvVariablesWithSeparator():Promise<Record<string, main.EnvVariableData>>;
 
 export function GetExecutionLog(arg1:string):Promise<main.ExecutionLog>;
 
+export function GetExecutionStats(arg1:string):Promise<main.RunStats>;
+
+export function GetLastCleanupReport():Promise<main.CleanupReport>;
+
+export function GetMetricsStatus():Promise<main.MetricsStatus>;
+
+export function GetRunFailureGroups(arg1:string):Promise<main.FailureAggregation>;
+
+export function GetScheduleHistory(arg1:string,arg2:number):Promise<main.ScheduleHistory>;
+
+export function GetScheduleTimeline(arg1:string,arg2:string):Promise<main.ScheduleTimeline>;
+
 export function GetScheduledTasks():Promise<Array<string>>;
 
+export function GetSettings():Promise<main.AppSettings>;
+
 export function GetTaskCount():Promise<number>;
 
+export function GetTaskFailureGroups(arg1:string,arg2:string,arg3:string):Promise<main.FailureAggregation>;
+
 export function GetTaskLogEntries(arg1:string):Promise<Array<main.TaskLogEntry>>;
 
 export function GetTaskLogs(arg1:string):Promise<Array<string>>;
@@ -28,12 +60,34 @@ export function GetTaskProgress(arg1:string):Promise<main.TaskProgress>;
 
 export function GetTaskScheduleInfo(arg1:string):Promise<main.TaskScheduleInfo>;
 
+export function GetTaskTrend(arg1:string,arg2:string,arg3:string,arg4:string):Promise<main.TaskTrend>;
+
 export function GetTasks(arg1:number,arg2:number):Promise<main.TaskList>;
 
 export function GetVersionInfo():Promise<main.VersionInfo>;
 
+export function ImportHTTPFile(arg1:string,arg2:boolean):Promise<main.ImportReport>;
+
+export function ImportOpenAPISpec(arg1:string,arg2:string):Promise<main.ImportReport>;
+
+export function ImportPostmanCollection(arg1:string):Promise<main.ImportReport>;
+
+export function ImportPostmanEnvironment(arg1:string,arg2:boolean):Promise<main.ImportReport>;
+
+export function ImportTaskFromCurl(arg1:string,arg2:string,arg3:Array<string>):Promise<string>;
+
+export function ImportWorkspace(arg1:string,arg2:string):Promise<main.WorkspaceImportReport>;
+
+export function ParseCurlCommand(arg1:string):Promise<main.CurlImportResult>;
+
 export function PreviewTaskWithVariables(arg1:string):Promise<Record<string, any>>;
 
+export function RegenerateAPIToken():Promise<string>;
+
+export function RunLogCleanup():Promise<main.CleanupReport>;
+
+export function SaveSettings(arg1:string):Promise<string>;
+
 export function SaveTask(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:number,arg7:number,arg8:number,arg9:number,arg10:Array<string>,arg11:string,arg12:main.SuccessCondition):Promise<string>;
 
 export function ScheduleTask(arg1:string):Promise<string>;
@@ -42,8 +96,18 @@ export function SetEnvVariable(arg1:string,arg2:string):Promise<string>;
 
 export function SetEnvVariableWithSeparator(arg1:string,arg2:string):Promise<string>;
 
+export function SetTaskCapture(arg1:string,arg2:string):Promise<string>;
+
+export function SetTaskNotifications(arg1:string,arg2:string):Promise<string>;
+
+export function SetTaskRetention(arg1:string,arg2:string):Promise<string>;
+
+export function SetTaskScheduleOptions(arg1:string,arg2:string):Promise<string>;
+
 export function StopTask(arg1:string):Promise<string>;
 
+export function TestNotificationChannel(arg1:string):Promise<string>;
+
 export function TestTask(arg1:string):Promise<string>;
 
 export function TestTaskDataWithBackend(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:main.SuccessCondition):Promise<main.TestTaskResult>;
@@ -18,6 +18,38 @@ export function ExecuteTask(arg1) {
   return window['go']['main']['App']['ExecuteTask'](arg1);
 }
 
+export function ExportExecutionLogCSV(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportExecutionLogCSV'](arg1, arg2, arg3);
+}
+
+export function ExportExecutionLogHAR(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportExecutionLogHAR'](arg1, arg2, arg3);
+}
+
+export function ExportExecutionLogJUnit(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportExecutionLogJUnit'](arg1, arg2, arg3);
+}
+
+export function ExportTaskAsCurl(arg1, arg2) {
+  return window['go']['main']['App']['ExportTaskAsCurl'](arg1, arg2);
+}
+
+export function ExportTasksAsHTTPFile(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportTasksAsHTTPFile'](arg1, arg2, arg3);
+}
+
+export function ExportWorkspace(arg1, arg2) {
+  return window['go']['main']['App']['ExportWorkspace'](arg1, arg2);
+}
+
+export function GetAPIStatus() {
+  return window['go']['main']['App']['GetAPIStatus']();
+}
+
+export function GetDaemonStatus() {
+  return window['go']['main']['App']['GetDaemonStatus']();
+}
+
 export function GetEnvVariables() {
   return window['go']['main']['App']['GetEnvVariables']();
 }
@@ -30,14 +62,46 @@ export function GetExecutionLog(arg1) {
   return window['go']['main']['App']['GetExecutionLog'](arg1);
 }
 
+export function GetExecutionStats(arg1) {
+  return window['go']['main']['App']['GetExecutionStats'](arg1);
+}
+
+export function GetLastCleanupReport() {
+  return window['go']['main']['App']['GetLastCleanupReport']();
+}
+
+export function GetMetricsStatus() {
+  return window['go']['main']['App']['GetMetricsStatus']();
+}
+
+export function GetRunFailureGroups(arg1) {
+  return window['go']['main']['App']['GetRunFailureGroups'](arg1);
+}
+
+export function GetScheduleHistory(arg1, arg2) {
+  return window['go']['main']['App']['GetScheduleHistory'](arg1, arg2);
+}
+
+export function GetScheduleTimeline(arg1, arg2) {
+  return window['go']['main']['App']['GetScheduleTimeline'](arg1, arg2);
+}
+
 export function GetScheduledTasks() {
   return window['go']['main']['App']['GetScheduledTasks']();
 }
 
+export function GetSettings() {
+  return window['go']['main']['App']['GetSettings']();
+}
+
 export function GetTaskCount() {
   return window['go']['main']['App']['GetTaskCount']();
 }
 
+export function GetTaskFailureGroups(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetTaskFailureGroups'](arg1, arg2, arg3);
+}
+
 export function GetTaskLogEntries(arg1) {
   return window['go']['main']['App']['GetTaskLogEntries'](arg1);
 }
@@ -54,6 +118,10 @@ export function GetTaskScheduleInfo(arg1) {
   return window['go']['main']['App']['GetTaskScheduleInfo'](arg1);
 }
 
+export function GetTaskTrend(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GetTaskTrend'](arg1, arg2, arg3, arg4);
+}
+
 export function GetTasks(arg1, arg2) {
   return window['go']['main']['App']['GetTasks'](arg1, arg2);
 }
@@ -62,10 +130,50 @@ export function GetVersionInfo() {
   return window['go']['main']['App']['GetVersionInfo']();
 }
 
+export function ImportHTTPFile(arg1, arg2) {
+  return window['go']['main']['App']['ImportHTTPFile'](arg1, arg2);
+}
+
+export function ImportOpenAPISpec(arg1, arg2) {
+  return window['go']['main']['App']['ImportOpenAPISpec'](arg1, arg2);
+}
+
+export function ImportPostmanCollection(arg1) {
+  return window['go']['main']['App']['ImportPostmanCollection'](arg1);
+}
+
+export function ImportPostmanEnvironment(arg1, arg2) {
+  return window['go']['main']['App']['ImportPostmanEnvironment'](arg1, arg2);
+}
+
+export function ImportTaskFromCurl(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ImportTaskFromCurl'](arg1, arg2, arg3);
+}
+
+export function ImportWorkspace(arg1, arg2) {
+  return window['go']['main']['App']['ImportWorkspace'](arg1, arg2);
+}
+
+export function ParseCurlCommand(arg1) {
+  return window['go']['main']['App']['ParseCurlCommand'](arg1);
+}
+
 export function PreviewTaskWithVariables(arg1) {
   return window['go']['main']['App']['PreviewTaskWithVariables'](arg1);
 }
 
+export function RegenerateAPIToken() {
+  return window['go']['main']['App']['RegenerateAPIToken']();
+}
+
+export function RunLogCleanup() {
+  return window['go']['main']['App']['RunLogCleanup']();
+}
+
+export function SaveSettings(arg1) {
+  return window['go']['main']['App']['SaveSettings'](arg1);
+}
+
 export function SaveTask(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10, arg11, arg12) {
   return window['go']['main']['App']['SaveTask'](arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10, arg11, arg12);
 }
@@ -82,10 +190,30 @@ export function SetEnvVariableWithSeparator(arg1, arg2) {
   return window['go']['main']['App']['SetEnvVariableWithSeparator'](arg1, arg2);
 }
 
+export function SetTaskCapture(arg1, arg2) {
+  return window['go']['main']['App']['SetTaskCapture'](arg1, arg2);
+}
+
+export function SetTaskNotifications(arg1, arg2) {
+  return window['go']['main']['App']['SetTaskNotifications'](arg1, arg2);
+}
+
+export function SetTaskRetention(arg1, arg2) {
+  return window['go']['main']['App']['SetTaskRetention'](arg1, arg2);
+}
+
+export function SetTaskScheduleOptions(arg1, arg2) {
+  return window['go']['main']['App']['SetTaskScheduleOptions'](arg1, arg2);
+}
+
 export function StopTask(arg1) {
   return window['go']['main']['App']['StopTask'](arg1);
 }
 
+export function TestNotificationChannel(arg1) {
+  return window['go']['main']['App']['TestNotificationChannel'](arg1);
+}
+
 export function TestTask(arg1) {
   return window['go']['main']['App']['TestTask'](arg1);
 }
@@ -1,5 +1,488 @@
 export namespace main {
 	
+	export class APISettings {
+	    enabled: boolean;
+	    port: number;
+	    token: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new APISettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.port = source["port"];
+	        this.token = source["token"];
+	    }
+	}
+	export class APIStatus {
+	    enabled: boolean;
+	    running: boolean;
+	    address: string;
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new APIStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.running = source["running"];
+	        this.address = source["address"];
+	        this.error = source["error"];
+	    }
+	}
+	export class NotificationRules {
+	    onFailure: boolean;
+	    onPartial: boolean;
+	    onRecovery: boolean;
+	    successRateBelow: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new NotificationRules(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.onFailure = source["onFailure"];
+	        this.onPartial = source["onPartial"];
+	        this.onRecovery = source["onRecovery"];
+	        this.successRateBelow = source["successRateBelow"];
+	    }
+	}
+	export class EmailSettings {
+	    host: string;
+	    port: number;
+	    security: string;
+	    username: string;
+	    password: string;
+	    skipVerify: boolean;
+	    from: string;
+	    to: string[];
+	    attachment: string;
+	    linkTemplate: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EmailSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.host = source["host"];
+	        this.port = source["port"];
+	        this.security = source["security"];
+	        this.username = source["username"];
+	        this.password = source["password"];
+	        this.skipVerify = source["skipVerify"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.attachment = source["attachment"];
+	        this.linkTemplate = source["linkTemplate"];
+	    }
+	}
+	export class NotificationChannel {
+	    name: string;
+	    type: string;
+	    enabled: boolean;
+	    url: string;
+	    headers: Record<string, string>;
+	    bodyTemplate: string;
+	    secret: string;
+	    email?: EmailSettings;
+	    rules: NotificationRules;
+	
+	    static createFrom(source: any = {}) {
+	        return new NotificationChannel(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.enabled = source["enabled"];
+	        this.url = source["url"];
+	        this.headers = source["headers"];
+	        this.bodyTemplate = source["bodyTemplate"];
+	        this.secret = source["secret"];
+	        this.email = this.convertValues(source["email"], EmailSettings);
+	        this.rules = this.convertValues(source["rules"], NotificationRules);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class NotificationConfig {
+	    channels: NotificationChannel[];
+	
+	    static createFrom(source: any = {}) {
+	        return new NotificationConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.channels = this.convertValues(source["channels"], NotificationChannel);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MetricsSettings {
+	    enabled: boolean;
+	    address: string;
+	    token: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MetricsSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.address = source["address"];
+	        this.token = source["token"];
+	    }
+	}
+	export class CaptureConfig {
+	    enabled: boolean;
+	    captureRequestBody: boolean;
+	    maxRequestBodyBytes: number;
+	    maskSensitiveHeaders: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CaptureConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.captureRequestBody = source["captureRequestBody"];
+	        this.maxRequestBodyBytes = source["maxRequestBodyBytes"];
+	        this.maskSensitiveHeaders = source["maskSensitiveHeaders"];
+	    }
+	}
+	export class RetentionPolicy {
+	    maxAgeDays: number;
+	    maxRuns: number;
+	    maxResponseBytes: number;
+	    keepSuccessBodies: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new RetentionPolicy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.maxAgeDays = source["maxAgeDays"];
+	        this.maxRuns = source["maxRuns"];
+	        this.maxResponseBytes = source["maxResponseBytes"];
+	        this.keepSuccessBodies = source["keepSuccessBodies"];
+	    }
+	}
+	export class AppSettings {
+	    retention: RetentionPolicy;
+	    cleanupIntervalMinutes: number;
+	    capture: CaptureConfig;
+	    api: APISettings;
+	    metrics: MetricsSettings;
+	    notifications: NotificationConfig;
+	
+	    static createFrom(source: any = {}) {
+	        return new AppSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.retention = this.convertValues(source["retention"], RetentionPolicy);
+	        this.cleanupIntervalMinutes = source["cleanupIntervalMinutes"];
+	        this.capture = this.convertValues(source["capture"], CaptureConfig);
+	        this.api = this.convertValues(source["api"], APISettings);
+	        this.metrics = this.convertValues(source["metrics"], MetricsSettings);
+	        this.notifications = this.convertValues(source["notifications"], NotificationConfig);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class CleanupReport {
+	    time: string;
+	    trigger: string;
+	    removedTaskLogs: number;
+	    removedExecutionLogs: number;
+	    removedHistory: number;
+	    truncatedResponses: number;
+	    bytesBefore: number;
+	    bytesAfter: number;
+	    reclaimedBytes: number;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CleanupReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = source["time"];
+	        this.trigger = source["trigger"];
+	        this.removedTaskLogs = source["removedTaskLogs"];
+	        this.removedExecutionLogs = source["removedExecutionLogs"];
+	        this.removedHistory = source["removedHistory"];
+	        this.truncatedResponses = source["truncatedResponses"];
+	        this.bytesBefore = source["bytesBefore"];
+	        this.bytesAfter = source["bytesAfter"];
+	        this.reclaimedBytes = source["reclaimedBytes"];
+	        this.message = source["message"];
+	    }
+	}
+	export class ScheduleOptions {
+	    overlap: string;
+	    timeZone: string;
+	    jitter: number;
+	    startDate: string;
+	    endDate: string;
+	    dailyStart: string;
+	    dailyEnd: string;
+	    misfire: string;
+	    misfireLimit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScheduleOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.overlap = source["overlap"];
+	        this.timeZone = source["timeZone"];
+	        this.jitter = source["jitter"];
+	        this.startDate = source["startDate"];
+	        this.endDate = source["endDate"];
+	        this.dailyStart = source["dailyStart"];
+	        this.dailyEnd = source["dailyEnd"];
+	        this.misfire = source["misfire"];
+	        this.misfireLimit = source["misfireLimit"];
+	    }
+	}
+	export class TaskSource {
+	    type: string;
+	    path: string;
+	    key: string;
+	    checksum: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskSource(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.path = source["path"];
+	        this.key = source["key"];
+	        this.checksum = source["checksum"];
+	    }
+	}
+	export class SuccessCondition {
+	    enabled: boolean;
+	    jsonPath: string;
+	    operator: string;
+	    expectedValue: string;
+	    statusCodes: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SuccessCondition(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.jsonPath = source["jsonPath"];
+	        this.operator = source["operator"];
+	        this.expectedValue = source["expectedValue"];
+	        this.statusCodes = source["statusCodes"];
+	    }
+	}
+	export class Task {
+	    id: string;
+	    name: string;
+	    url: string;
+	    method: string;
+	    headers: Record<string, string>;
+	    headersText: string;
+	    data: string;
+	    times: number;
+	    threads: number;
+	    delayMin: number;
+	    delayMax: number;
+	    tags: string[];
+	    cronExpr: string;
+	    successCondition: SuccessCondition;
+	    createdAt: number;
+	    updatedAt: number;
+	    isRunning: boolean;
+	    lastRunTime: string;
+	    lastRunStatus: string;
+	    lastRunResult: string;
+	    retention?: RetentionPolicy;
+	    capture?: CaptureConfig;
+	    insecure: boolean;
+	    proxy: string;
+	    source?: TaskSource;
+	    notifications?: NotificationConfig;
+	    schedule?: ScheduleOptions;
+	
+	    static createFrom(source: any = {}) {
+	        return new Task(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.url = source["url"];
+	        this.method = source["method"];
+	        this.headers = source["headers"];
+	        this.headersText = source["headersText"];
+	        this.data = source["data"];
+	        this.times = source["times"];
+	        this.threads = source["threads"];
+	        this.delayMin = source["delayMin"];
+	        this.delayMax = source["delayMax"];
+	        this.tags = source["tags"];
+	        this.cronExpr = source["cronExpr"];
+	        this.successCondition = this.convertValues(source["successCondition"], SuccessCondition);
+	        this.createdAt = source["createdAt"];
+	        this.updatedAt = source["updatedAt"];
+	        this.isRunning = source["isRunning"];
+	        this.lastRunTime = source["lastRunTime"];
+	        this.lastRunStatus = source["lastRunStatus"];
+	        this.lastRunResult = source["lastRunResult"];
+	        this.retention = this.convertValues(source["retention"], RetentionPolicy);
+	        this.capture = this.convertValues(source["capture"], CaptureConfig);
+	        this.insecure = source["insecure"];
+	        this.proxy = source["proxy"];
+	        this.source = this.convertValues(source["source"], TaskSource);
+	        this.notifications = this.convertValues(source["notifications"], NotificationConfig);
+	        this.schedule = this.convertValues(source["schedule"], ScheduleOptions);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CurlImportResult {
+	    task?: Task;
+	    warnings: string[];
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CurlImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.task = this.convertValues(source["task"], Task);
+	        this.warnings = source["warnings"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DaemonStatus {
+	    running: boolean;
+	    pid: number;
+	    startedAt: string;
+	    lastHeartbeat: string;
+	    version: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DaemonStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.running = source["running"];
+	        this.pid = source["pid"];
+	        this.startedAt = source["startedAt"];
+	        this.lastHeartbeat = source["lastHeartbeat"];
+	        this.version = source["version"];
+	    }
+	}
 	export class SuccessConditionDetails {
 	    type: string;
 	    jsonPath: string;
@@ -33,29 +516,248 @@ export namespace main {
 	    responseTime: number;
 	    response: string;
 	    error: string;
-	    success: boolean;
-	    successConditionDetails?: SuccessConditionDetails;
-	    errorType: string;
-	    detailedError: string;
+	    success: boolean;
+	    successConditionDetails?: SuccessConditionDetails;
+	    errorType: string;
+	    detailedError: string;
+	    requestHeaders: Record<string, string>;
+	    requestBody: string;
+	    requestBodySize: number;
+	    responseHeaders: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new DetailedLogEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.requestId = source["requestId"];
+	        this.timestamp = source["timestamp"];
+	        this.url = source["url"];
+	        this.method = source["method"];
+	        this.statusCode = source["statusCode"];
+	        this.responseTime = source["responseTime"];
+	        this.response = source["response"];
+	        this.error = source["error"];
+	        this.success = source["success"];
+	        this.successConditionDetails = this.convertValues(source["successConditionDetails"], SuccessConditionDetails);
+	        this.errorType = source["errorType"];
+	        this.detailedError = source["detailedError"];
+	        this.requestHeaders = source["requestHeaders"];
+	        this.requestBody = source["requestBody"];
+	        this.requestBodySize = source["requestBodySize"];
+	        this.responseHeaders = source["responseHeaders"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ThroughputPoint {
+	    second: number;
+	    timestamp: string;
+	    requests: number;
+	    successCount: number;
+	    failedCount: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ThroughputPoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.second = source["second"];
+	        this.timestamp = source["timestamp"];
+	        this.requests = source["requests"];
+	        this.successCount = source["successCount"];
+	        this.failedCount = source["failedCount"];
+	    }
+	}
+	export class LatencyBucket {
+	    upperBound: number;
+	    label: string;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LatencyBucket(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.upperBound = source["upperBound"];
+	        this.label = source["label"];
+	        this.count = source["count"];
+	    }
+	}
+	export class RunStats {
+	    sampleCount: number;
+	    min: number;
+	    max: number;
+	    mean: number;
+	    stdDev: number;
+	    p50: number;
+	    p90: number;
+	    p95: number;
+	    p99: number;
+	    histogram: LatencyBucket[];
+	    throughput: ThroughputPoint[];
+	    avgRps: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RunStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sampleCount = source["sampleCount"];
+	        this.min = source["min"];
+	        this.max = source["max"];
+	        this.mean = source["mean"];
+	        this.stdDev = source["stdDev"];
+	        this.p50 = source["p50"];
+	        this.p90 = source["p90"];
+	        this.p95 = source["p95"];
+	        this.p99 = source["p99"];
+	        this.histogram = this.convertValues(source["histogram"], LatencyBucket);
+	        this.throughput = this.convertValues(source["throughput"], ThroughputPoint);
+	        this.avgRps = source["avgRps"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ExecutionLog {
+	    taskLogId: string;
+	    detailedLogs: DetailedLogEntry[];
+	    summary: string;
+	    totalRequests: number;
+	    successCount: number;
+	    failedCount: number;
+	    duration: number;
+	    stats?: RunStats;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExecutionLog(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.taskLogId = source["taskLogId"];
+	        this.detailedLogs = this.convertValues(source["detailedLogs"], DetailedLogEntry);
+	        this.summary = source["summary"];
+	        this.totalRequests = source["totalRequests"];
+	        this.successCount = source["successCount"];
+	        this.failedCount = source["failedCount"];
+	        this.duration = source["duration"];
+	        this.stats = this.convertValues(source["stats"], RunStats);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FailureGroup {
+	    key: string;
+	    summary: string;
+	    errorType: string;
+	    statusCode: number;
+	    message: string;
+	    actualValue: string;
+	    count: number;
+	    firstOccurrence: string;
+	    lastOccurrence: string;
+	    sampleRequestIds: string[];
+	    taskLogIds: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new FailureGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.summary = source["summary"];
+	        this.errorType = source["errorType"];
+	        this.statusCode = source["statusCode"];
+	        this.message = source["message"];
+	        this.actualValue = source["actualValue"];
+	        this.count = source["count"];
+	        this.firstOccurrence = source["firstOccurrence"];
+	        this.lastOccurrence = source["lastOccurrence"];
+	        this.sampleRequestIds = source["sampleRequestIds"];
+	        this.taskLogIds = source["taskLogIds"];
+	    }
+	}
+	export class FailureAggregation {
+	    taskId: string;
+	    taskLogId: string;
+	    startTime: string;
+	    endTime: string;
+	    runCount: number;
+	    totalRequests: number;
+	    failedCount: number;
+	    groups: FailureGroup[];
+	    error: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new DetailedLogEntry(source);
+	        return new FailureAggregation(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.requestId = source["requestId"];
-	        this.timestamp = source["timestamp"];
-	        this.url = source["url"];
-	        this.method = source["method"];
-	        this.statusCode = source["statusCode"];
-	        this.responseTime = source["responseTime"];
-	        this.response = source["response"];
+	        this.taskId = source["taskId"];
+	        this.taskLogId = source["taskLogId"];
+	        this.startTime = source["startTime"];
+	        this.endTime = source["endTime"];
+	        this.runCount = source["runCount"];
+	        this.totalRequests = source["totalRequests"];
+	        this.failedCount = source["failedCount"];
+	        this.groups = this.convertValues(source["groups"], FailureGroup);
 	        this.error = source["error"];
-	        this.success = source["success"];
-	        this.successConditionDetails = this.convertValues(source["successConditionDetails"], SuccessConditionDetails);
-	        this.errorType = source["errorType"];
-	        this.detailedError = source["detailedError"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -76,28 +778,100 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class ExecutionLog {
+	
+	export class ImportReport {
+	    source: string;
+	    importedTasks: number;
+	    updatedTasks: number;
+	    taskIds: string[];
+	    importedVariables: number;
+	    warnings: string[];
+	    message: string;
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source = source["source"];
+	        this.importedTasks = source["importedTasks"];
+	        this.updatedTasks = source["updatedTasks"];
+	        this.taskIds = source["taskIds"];
+	        this.importedVariables = source["importedVariables"];
+	        this.warnings = source["warnings"];
+	        this.message = source["message"];
+	        this.error = source["error"];
+	    }
+	}
+	
+	
+	export class MetricsStatus {
+	    enabled: boolean;
+	    running: boolean;
+	    address: string;
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MetricsStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.running = source["running"];
+	        this.address = source["address"];
+	        this.error = source["error"];
+	    }
+	}
+	
+	
+	
+	
+	
+	export class ScheduleFire {
+	    id: number;
+	    taskId: string;
+	    scheduledAt: string;
+	    firedAt: string;
+	    delay: number;
+	    action: string;
+	    reason: string;
 	    taskLogId: string;
-	    detailedLogs: DetailedLogEntry[];
-	    summary: string;
-	    totalRequests: number;
-	    successCount: number;
-	    failedCount: number;
-	    duration: number;
+	    status: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new ExecutionLog(source);
+	        return new ScheduleFire(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.taskId = source["taskId"];
+	        this.scheduledAt = source["scheduledAt"];
+	        this.firedAt = source["firedAt"];
+	        this.delay = source["delay"];
+	        this.action = source["action"];
+	        this.reason = source["reason"];
 	        this.taskLogId = source["taskLogId"];
-	        this.detailedLogs = this.convertValues(source["detailedLogs"], DetailedLogEntry);
-	        this.summary = source["summary"];
-	        this.totalRequests = source["totalRequests"];
-	        this.successCount = source["successCount"];
-	        this.failedCount = source["failedCount"];
-	        this.duration = source["duration"];
+	        this.status = source["status"];
+	    }
+	}
+	export class ScheduleHistory {
+	    taskId: string;
+	    fires: ScheduleFire[];
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScheduleHistory(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.taskId = source["taskId"];
+	        this.fires = this.convertValues(source["fires"], ScheduleFire);
+	        this.error = source["error"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -118,73 +892,61 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class SuccessCondition {
-	    enabled: boolean;
-	    jsonPath: string;
-	    operator: string;
-	    expectedValue: string;
+	
+	export class TimelineCluster {
+	    time: string;
+	    count: number;
+	    taskIds: string[];
 	
 	    static createFrom(source: any = {}) {
-	        return new SuccessCondition(source);
+	        return new TimelineCluster(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.enabled = source["enabled"];
-	        this.jsonPath = source["jsonPath"];
-	        this.operator = source["operator"];
-	        this.expectedValue = source["expectedValue"];
+	        this.time = source["time"];
+	        this.count = source["count"];
+	        this.taskIds = source["taskIds"];
 	    }
 	}
+	export class TimelineRun {
+	    time: string;
+	    taskId: string;
+	    taskName: string;
+	    jitter: number;
 	
-	export class Task {
-	    id: string;
-	    name: string;
-	    url: string;
-	    method: string;
-	    headers: Record<string, string>;
-	    headersText: string;
-	    data: string;
-	    times: number;
-	    threads: number;
-	    delayMin: number;
-	    delayMax: number;
-	    tags: string[];
-	    cronExpr: string;
-	    successCondition: SuccessCondition;
-	    createdAt: number;
-	    updatedAt: number;
-	    isRunning: boolean;
-	    lastRunTime: string;
-	    lastRunStatus: string;
-	    lastRunResult: string;
+	    static createFrom(source: any = {}) {
+	        return new TimelineRun(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = source["time"];
+	        this.taskId = source["taskId"];
+	        this.taskName = source["taskName"];
+	        this.jitter = source["jitter"];
+	    }
+	}
+	export class ScheduleTimeline {
+	    startTime: string;
+	    endTime: string;
+	    runs: TimelineRun[];
+	    clusters: TimelineCluster[];
+	    truncated: boolean;
+	    error: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new Task(source);
+	        return new ScheduleTimeline(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.name = source["name"];
-	        this.url = source["url"];
-	        this.method = source["method"];
-	        this.headers = source["headers"];
-	        this.headersText = source["headersText"];
-	        this.data = source["data"];
-	        this.times = source["times"];
-	        this.threads = source["threads"];
-	        this.delayMin = source["delayMin"];
-	        this.delayMax = source["delayMax"];
-	        this.tags = source["tags"];
-	        this.cronExpr = source["cronExpr"];
-	        this.successCondition = this.convertValues(source["successCondition"], SuccessCondition);
-	        this.createdAt = source["createdAt"];
-	        this.updatedAt = source["updatedAt"];
-	        this.isRunning = source["isRunning"];
-	        this.lastRunTime = source["lastRunTime"];
-	        this.lastRunStatus = source["lastRunStatus"];
-	        this.lastRunResult = source["lastRunResult"];
+	        this.startTime = source["startTime"];
+	        this.endTime = source["endTime"];
+	        this.runs = this.convertValues(source["runs"], TimelineRun);
+	        this.clusters = this.convertValues(source["clusters"], TimelineCluster);
+	        this.truncated = source["truncated"];
+	        this.error = source["error"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -205,6 +967,9 @@ export namespace main {
 		    return a;
 		}
 	}
+	
+	
+	
 	export class TaskList {
 	    tasks: Record<string, Task>;
 	    total: number;
@@ -287,6 +1052,8 @@ export namespace main {
 	    lastRunTime: string;
 	    lastRunStatus: string;
 	    lastRunResult: string;
+	    timeZone: string;
+	    upcomingRuns: string[];
 	
 	    static createFrom(source: any = {}) {
 	        return new TaskScheduleInfo(source);
@@ -303,8 +1070,83 @@ export namespace main {
 	        this.lastRunTime = source["lastRunTime"];
 	        this.lastRunStatus = source["lastRunStatus"];
 	        this.lastRunResult = source["lastRunResult"];
+	        this.timeZone = source["timeZone"];
+	        this.upcomingRuns = source["upcomingRuns"];
+	    }
+	}
+	
+	export class TrendPoint {
+	    time: string;
+	    taskLogId: string;
+	    status: string;
+	    runCount: number;
+	    totalRequests: number;
+	    successCount: number;
+	    failedCount: number;
+	    successRate: number;
+	    avgLatency: number;
+	    p95Latency: number;
+	    maxP95Latency: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TrendPoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = source["time"];
+	        this.taskLogId = source["taskLogId"];
+	        this.status = source["status"];
+	        this.runCount = source["runCount"];
+	        this.totalRequests = source["totalRequests"];
+	        this.successCount = source["successCount"];
+	        this.failedCount = source["failedCount"];
+	        this.successRate = source["successRate"];
+	        this.avgLatency = source["avgLatency"];
+	        this.p95Latency = source["p95Latency"];
+	        this.maxP95Latency = source["maxP95Latency"];
 	    }
 	}
+	export class TaskTrend {
+	    taskId: string;
+	    granularity: string;
+	    startTime: string;
+	    endTime: string;
+	    points: TrendPoint[];
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskTrend(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.taskId = source["taskId"];
+	        this.granularity = source["granularity"];
+	        this.startTime = source["startTime"];
+	        this.endTime = source["endTime"];
+	        this.points = this.convertValues(source["points"], TrendPoint);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class TestTaskResult {
 	    success: boolean;
 	    statusCode: number;
@@ -359,6 +1201,10 @@ export namespace main {
 		    return a;
 		}
 	}
+	
+	
+	
+	
 	export class VersionInfo {
 	    version: string;
 	    name: string;
@@ -375,6 +1221,78 @@ export namespace main {
 	        this.buildDate = source["buildDate"];
 	    }
 	}
+	export class WorkspaceChange {
+	    kind: string;
+	    name: string;
+	    id: string;
+	    action: string;
+	    conflictWith: string;
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkspaceChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.name = source["name"];
+	        this.id = source["id"];
+	        this.action = source["action"];
+	        this.conflictWith = source["conflictWith"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class WorkspaceImportReport {
+	    source: string;
+	    dryRun: boolean;
+	    version: number;
+	    changes: WorkspaceChange[];
+	    created: number;
+	    overwritten: number;
+	    renamed: number;
+	    skipped: number;
+	    warnings: string[];
+	    message: string;
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkspaceImportReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source = source["source"];
+	        this.dryRun = source["dryRun"];
+	        this.version = source["version"];
+	        this.changes = this.convertValues(source["changes"], WorkspaceChange);
+	        this.created = source["created"];
+	        this.overwritten = source["overwritten"];
+	        this.renamed = source["renamed"];
+	        this.skipped = source["skipped"];
+	        this.warnings = source["warnings"];
+	        this.message = source["message"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 
 }
 
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HAR 1.2 格式定义（http://www.softwareishard.com/blog/har-12-spec/）
+
+type harLog struct {
+	Log harContent `json:"log"`
+}
+
+type harContent struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Pages   []harPage  `json:"pages"`
+	Entries []harEntry `json:"entries"`
+	Comment string     `json:"comment,omitempty"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harPage struct {
+	StartedDateTime string         `json:"startedDateTime"`
+	ID              string         `json:"id"`
+	Title           string         `json:"title"`
+	PageTimings     harPageTimings `json:"pageTimings"`
+	Comment         string         `json:"comment,omitempty"`
+}
+
+type harPageTimings struct {
+	OnContentLoad float64 `json:"onContentLoad"`
+	OnLoad        float64 `json:"onLoad"`
+}
+
+type harEntry struct {
+	Pageref         string      `json:"pageref,omitempty"`
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+	// 自定义字段（HAR规范允许以下划线开头的扩展字段）
+	RequestID     string `json:"_requestId"`
+	Success       bool   `json:"_success"`
+	ErrorType     string `json:"_errorType,omitempty"`
+	Error         string `json:"_error,omitempty"`
+	DetailedError string `json:"_detailedError,omitempty"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harPostData struct {
+	MimeType string         `json:"mimeType"`
+	Params   []harNameValue `json:"params"`
+	Text     string         `json:"text"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harBody        `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harBody struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+// ExportExecutionLogHAR 将执行日志导出为HAR 1.2文件
+// filterJson 为 LogExportFilter 的JSON（为空时导出全部），path 为空时弹出保存对话框
+func (a *App) ExportExecutionLogHAR(taskLogID, filterJson, path string) string {
+	executionLog, err := a.getExportExecutionLog(taskLogID, filterJson)
+	if err != nil {
+		return fmt.Sprintf("错误：%v", err)
+	}
+
+	data, err := json.MarshalIndent(buildHAR(executionLog), "", "  ")
+	if err != nil {
+		return fmt.Sprintf("导出失败：%v", err)
+	}
+
+	path, err = a.resolveExportPath(path, exportFileName(taskLogID, "har"), "HAR文件 (*.har)", "*.har")
+	if err != nil {
+		return fmt.Sprintf("错误：%v", err)
+	}
+
+	if err := writeExportFile(path, data); err != nil {
+		return fmt.Sprintf("导出失败：%v", err)
+	}
+
+	return fmt.Sprintf("已导出 %d 条请求到 %s", len(executionLog.DetailedLogs), path)
+}
+
+// buildHAR 根据执行日志构建HAR文档
+func buildHAR(executionLog *ExecutionLog) harLog {
+	pageID := executionLog.TaskLogID
+	entries := make([]harEntry, 0, len(executionLog.DetailedLogs))
+
+	var pageStart time.Time
+	for _, detail := range executionLog.DetailedLogs {
+		entry := buildHAREntry(detail)
+		entry.Pageref = pageID
+		entries = append(entries, entry)
+
+		if started, err := time.Parse(time.RFC3339Nano, entry.StartedDateTime); err == nil {
+			if pageStart.IsZero() || started.Before(pageStart) {
+				pageStart = started
+			}
+		}
+	}
+	if pageStart.IsZero() {
+		pageStart = time.Now()
+	}
+
+	return harLog{
+		Log: harContent{
+			Version: "1.2",
+			Creator: harCreator{Name: AppName, Version: AppVersion},
+			Pages: []harPage{
+				{
+					StartedDateTime: pageStart.Format(time.RFC3339Nano),
+					ID:              pageID,
+					Title:           executionLog.Summary,
+					PageTimings:     harPageTimings{OnContentLoad: -1, OnLoad: -1},
+					Comment: fmt.Sprintf("总请求: %d, 成功: %d, 失败: %d, 耗时: %d秒",
+						executionLog.TotalRequests, executionLog.SuccessCount, executionLog.FailedCount, executionLog.Duration),
+				},
+			},
+			Entries: entries,
+		},
+	}
+}
+
+// buildHAREntry 将单条详细日志转换为HAR条目
+func buildHAREntry(detail DetailedLogEntry) harEntry {
+	// 日志时间戳记录的是请求完成时间，开始时间需减去响应时间
+	started := time.Now()
+	if finished, err := time.ParseInLocation("2006-01-02 15:04:05", detail.Timestamp, time.Local); err == nil {
+		started = finished.Add(-time.Duration(detail.ResponseTime) * time.Millisecond)
+	}
+
+	request := harRequest{
+		Method:      detail.Method,
+		URL:         detail.URL,
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []harNameValue{},
+		Headers:     harHeaders(detail.RequestHeaders),
+		QueryString: harQueryString(detail.URL),
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+	if detail.RequestHeaders != nil {
+		request.BodySize = detail.RequestBodySize
+	}
+	if detail.RequestBody != "" {
+		request.PostData = &harPostData{
+			MimeType: headerValue(detail.RequestHeaders, "Content-Type"),
+			Params:   []harNameValue{},
+			Text:     detail.RequestBody,
+		}
+	}
+
+	response := harResponse{
+		Status:      detail.StatusCode,
+		StatusText:  http.StatusText(detail.StatusCode),
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []harNameValue{},
+		Headers:     harHeaders(detail.ResponseHeaders),
+		Content: harBody{
+			Size:     len(detail.Response),
+			MimeType: headerValue(detail.ResponseHeaders, "Content-Type"),
+			Text:     detail.Response,
+		},
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+	if detail.StatusCode == 0 {
+		// 网络错误没有响应
+		response.HTTPVersion = ""
+	}
+	if response.Content.MimeType == "" {
+		response.Content.MimeType = "text/plain"
+	}
+
+	entry := harEntry{
+		StartedDateTime: started.Format(time.RFC3339Nano),
+		Time:            float64(detail.ResponseTime),
+		Request:         request,
+		Response:        response,
+		Timings: harTimings{
+			Blocked: -1,
+			DNS:     -1,
+			Connect: -1,
+			Send:    0,
+			Wait:    float64(detail.ResponseTime),
+			Receive: 0,
+			SSL:     -1,
+		},
+		RequestID:     detail.RequestID,
+		Success:       detail.Success,
+		ErrorType:     detail.ErrorType,
+		Error:         detail.Error,
+		DetailedError: detail.DetailedError,
+	}
+	if !detail.Success && detail.Error != "" {
+		entry.Comment = detail.Error
+	}
+
+	return entry
+}
+
+// harHeaders 将header映射转换为按名称排序的HAR列表
+func harHeaders(headers map[string]string) []harNameValue {
+	result := make([]harNameValue, 0, len(headers))
+	for name, value := range headers {
+		result = append(result, harNameValue{Name: name, Value: value})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// harQueryString 解析URL中的查询参数
+func harQueryString(rawURL string) []harNameValue {
+	result := []harNameValue{}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return result
+	}
+
+	for _, pair := range strings.Split(parsed.RawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(pair, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if unescaped, err := url.QueryUnescape(value); err == nil {
+			value = unescaped
+		}
+		result = append(result, harNameValue{Name: name, Value: value})
+	}
+
+	return result
+}
+
+// headerValue 不区分大小写地获取header值
+func headerValue(headers map[string]string, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,445 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// .http 文件中识别的HTTP方法
+var httpFileMethods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "DELETE": true, "PATCH": true,
+	"HEAD": true, "OPTIONS": true, "TRACE": true, "CONNECT": true,
+}
+
+// 变量定义：@name = value
+var httpFileVariablePattern = regexp.MustCompile(`^@([A-Za-z_][A-Za-z0-9_.-]*)\s*=\s*(.*)$`)
+
+// 变量引用：{{name}}
+var httpFileVariableRefPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_.-]*)\s*\}\}`)
+
+// httpFileRequest .http 文件中解析出的单个请求
+type httpFileRequest struct {
+	name    string
+	comment string // 请求前的第一行注释，没有名称时作为任务名称
+	tags    []string
+	method  string
+	url     string
+	headers [][2]string
+	body    []string
+}
+
+// ImportHTTPFile 导入 .http 文件（VS Code REST Client / JetBrains HTTP Client 格式），每个请求生成一个任务
+// @变量 定义导入为环境变量，overwriteVariables 为 false 时不覆盖已存在的变量
+// 再次导入同一文件时按请求名称更新已导入的任务；path 为空时弹出文件选择对话框
+func (a *App) ImportHTTPFile(path string, overwriteVariables bool) ImportReport {
+	path, data, err := a.readImportFile(path, "选择.http文件", "HTTP请求文件 (*.http;*.rest)", "*.http;*.rest")
+	report := newImportReport(path)
+	if err != nil {
+		return report.fail("%v", err)
+	}
+	if absPath, err := filepath.Abs(path); err == nil {
+		path = absPath
+		report.Source = absPath
+	}
+
+	requests, variables := parseHTTPFile(&report, string(data))
+	if len(requests) == 0 {
+		return report.fail("文件中没有请求")
+	}
+
+	now := time.Now().Unix()
+	keys := make(map[string]int)
+	generated := make([]*Task, 0, len(requests))
+	for _, request := range requests {
+		name := request.name
+		if name == "" {
+			name = request.comment
+		}
+		if name == "" {
+			name = defaultTaskNameFromURL(request.method, request.url)
+		}
+
+		// 同名请求使用序号区分，保证重新导入时能够对应
+		key := name
+		keys[name]++
+		if keys[name] > 1 {
+			key = fmt.Sprintf("%s #%d", name, keys[name])
+		}
+
+		tags := request.tags
+		if tags == nil {
+			tags = []string{}
+		}
+
+		headersText := headersToText(request.headers)
+		task := &Task{
+			ID:          newTaskID(),
+			Name:        name,
+			URL:         request.url,
+			Method:      request.method,
+			Headers:     a.parseHeadersText(headersText),
+			HeadersText: headersText,
+			Data:        strings.Join(request.body, "\n"),
+			Times:       1,
+			Threads:     1,
+			Tags:        tags,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		task.Source = &TaskSource{Type: "http", Path: path, Key: key, Checksum: importedTaskChecksum(task)}
+		generated = append(generated, task)
+	}
+
+	if err := a.syncImportedTasks(&report, "http", path, generated); err != nil {
+		return report.fail("保存任务失败：%v", err)
+	}
+
+	if len(variables) > 0 {
+		existing, err := a.dbGetAllEnvVariables()
+		if err != nil {
+			report.warn("读取环境变量失败，文件变量未导入：%v", err)
+		} else {
+			for _, variable := range variables {
+				current, exists := existing[variable[0]]
+				if exists && !overwriteVariables {
+					report.warn("变量 '%s' 已存在，未覆盖", variable[0])
+					continue
+				}
+				data := EnvVariableData{Value: variable[1], Separator: current.Separator}
+				if err := a.dbSetEnvVariable(variable[0], data); err != nil {
+					report.warn("导入变量 '%s' 失败：%v", variable[0], err)
+					continue
+				}
+				report.ImportedVariables++
+			}
+		}
+	}
+
+	report.Message = fmt.Sprintf("导入 %d 个任务、更新 %d 个任务、导入 %d 个变量，%d 条提示",
+		report.ImportedTasks, report.UpdatedTasks, report.ImportedVariables, len(report.Warnings))
+	return report
+}
+
+// ExportTasksAsHTTPFile 将任务导出为 .http 文件，taskIDs 为空时导出全部任务
+// includeVariables 为 true 时在文件开头写入任务引用的环境变量（@name = value，名称像密钥的变量不写入值）
+// path 为空时弹出保存对话框
+func (a *App) ExportTasksAsHTTPFile(taskIDs []string, includeVariables bool, path string) string {
+	tasks, err := a.tasksForExport(taskIDs)
+	if err != nil {
+		return fmt.Sprintf("错误：%v", err)
+	}
+
+	var variables map[string]EnvVariableData
+	if includeVariables {
+		variables, err = a.dbGetAllEnvVariables()
+		if err != nil {
+			return fmt.Sprintf("读取环境变量失败：%v", err)
+		}
+	}
+
+	path, err = a.resolveExportPath(path, "requests.http", "HTTP请求文件 (*.http)", "*.http")
+	if err != nil {
+		return fmt.Sprintf("错误：%v", err)
+	}
+
+	content, skipped, masked := buildHTTPFile(tasks, variables)
+	if len(skipped) == len(tasks) {
+		return fmt.Sprintf("错误：任务的请求体中有会被解析为请求分隔符、响应处理脚本或文件引用的行（###、> 、< 开头），无法导出为.http文件：%s", strings.Join(skipped, "、"))
+	}
+
+	if err := writeExportFile(path, []byte(content)); err != nil {
+		return fmt.Sprintf("导出失败：%v", err)
+	}
+
+	message := fmt.Sprintf("已导出 %d 个任务到 %s", len(tasks)-len(skipped), path)
+	if len(skipped) > 0 {
+		message += fmt.Sprintf("；请求体中有 ###、> 、< 开头的行，未导出：%s", strings.Join(skipped, "、"))
+	}
+	if len(masked) > 0 {
+		message += fmt.Sprintf("；变量可能包含密钥，未导出值：%s", strings.Join(masked, "、"))
+	}
+	return message
+}
+
+// tasksForExport 获取要导出的任务（按创建时间排序），taskIDs 为空时返回全部任务
+func (a *App) tasksForExport(taskIDs []string) ([]*Task, error) {
+	a.cacheMutex.RLock()
+	defer a.cacheMutex.RUnlock()
+
+	// 返回副本，执行中会更新缓存中任务的运行状态
+	var tasks []*Task
+	if len(taskIDs) == 0 {
+		for _, task := range a.tasksCache {
+			copied := *task
+			tasks = append(tasks, &copied)
+		}
+	} else {
+		for _, taskID := range taskIDs {
+			task, exists := a.tasksCache[taskID]
+			if !exists {
+				return nil, fmt.Errorf("任务不存在：%s", taskID)
+			}
+			copied := *task
+			tasks = append(tasks, &copied)
+		}
+	}
+
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("没有可导出的任务")
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].CreatedAt != tasks[j].CreatedAt {
+			return tasks[i].CreatedAt < tasks[j].CreatedAt
+		}
+		return tasks[i].ID < tasks[j].ID
+	})
+	return tasks, nil
+}
+
+// buildHTTPFile 生成 .http 文件内容，variables 不为空时写入任务引用到的变量
+// 返回内容、因请求体无法表示而跳过的任务名称和未导出值的敏感变量名称
+func buildHTTPFile(tasks []*Task, variables map[string]EnvVariableData) (string, []string, []string) {
+	var builder strings.Builder
+
+	// .http 格式没有转义方式，请求体中会被解析为其他内容的任务不导出
+	var skipped []string
+	exportable := make([]*Task, 0, len(tasks))
+	for _, task := range tasks {
+		if httpFileBodyConflict(task.Data) {
+			skipped = append(skipped, task.Name)
+			continue
+		}
+		exportable = append(exportable, task)
+	}
+
+	var masked []string
+	if len(variables) > 0 {
+		referenced := make(map[string]bool)
+		for _, task := range exportable {
+			for _, text := range []string{task.URL, task.HeadersText, task.Data} {
+				for _, match := range httpFileVariableRefPattern.FindAllStringSubmatch(text, -1) {
+					referenced[match[1]] = true
+				}
+			}
+		}
+
+		names := make([]string, 0, len(referenced))
+		for name := range referenced {
+			if _, exists := variables[name]; exists {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			// 敏感变量只写入注释，避免密钥以明文写入文件（重新导入时也不会覆盖已有的值）
+			if isSensitiveVariable(name) {
+				builder.WriteString(fmt.Sprintf("# 变量 %s 可能包含密钥，未导出值\n", name))
+				masked = append(masked, name)
+				continue
+			}
+			builder.WriteString(fmt.Sprintf("@%s = %s\n", name, variables[name].Value))
+		}
+		if len(names) > 0 {
+			builder.WriteString("\n")
+		}
+	}
+
+	for i, task := range exportable {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+
+		method := strings.ToUpper(task.Method)
+		if method == "" {
+			method = "GET"
+		}
+
+		builder.WriteString("### " + task.Name + "\n")
+		if len(task.Tags) > 0 {
+			builder.WriteString("# @tags " + strings.Join(task.Tags, ", ") + "\n")
+		}
+		builder.WriteString(method + " " + task.URL + "\n")
+		for _, header := range orderedTaskHeaders(task) {
+			builder.WriteString(header[0] + ": " + header[1] + "\n")
+		}
+		if task.Data != "" {
+			builder.WriteString("\n" + strings.TrimRight(task.Data, "\r\n") + "\n")
+		}
+	}
+
+	return builder.String(), skipped, masked
+}
+
+// httpFileBodyConflict 判断请求体中是否有导入时会被解析为请求分隔符（###）、响应处理脚本（> 、<> ）或文件引用（首行 < ）的行
+func httpFileBodyConflict(body string) bool {
+	first := true
+	for _, line := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if first && trimmed == "" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "###") || strings.HasPrefix(trimmed, "> ") || strings.HasPrefix(trimmed, "<> ") {
+			return true
+		}
+		if first && strings.HasPrefix(trimmed, "< ") {
+			return true
+		}
+		first = false
+	}
+	return false
+}
+
+// isSensitiveVariable 根据变量名判断变量是否可能包含密钥
+func isSensitiveVariable(name string) bool {
+	lowerName := strings.ToLower(name)
+	for _, keyword := range []string{"password", "passwd", "secret", "token", "key", "credential", "cookie", "authorization"} {
+		if strings.Contains(lowerName, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseHTTPFile 解析 .http 文件，返回请求列表和变量定义（按出现顺序）
+func parseHTTPFile(report *ImportReport, content string) ([]*httpFileRequest, [][2]string) {
+	content = strings.TrimPrefix(content, "\xEF\xBB\xBF")
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	var requests []*httpFileRequest
+	var variables [][2]string
+
+	const (
+		stateBefore  = iota // 请求行之前（注释、变量）
+		stateHeaders        // 请求行之后的查询参数和header
+		stateBody           // 请求体
+	)
+
+	request := &httpFileRequest{}
+	state := stateBefore
+	skipBody := false
+
+	finish := func() {
+		if request.method != "" {
+			// 去掉请求体末尾的空行
+			for len(request.body) > 0 && strings.TrimSpace(request.body[len(request.body)-1]) == "" {
+				request.body = request.body[:len(request.body)-1]
+			}
+			requests = append(requests, request)
+		}
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "###") {
+			finish()
+			request = &httpFileRequest{name: strings.TrimSpace(strings.TrimPrefix(trimmed, "###"))}
+			state = stateBefore
+			skipBody = false
+			continue
+		}
+
+		switch state {
+		case stateBefore:
+			if trimmed == "" {
+				continue
+			}
+			if comment, ok := httpFileComment(trimmed); ok {
+				switch {
+				case strings.HasPrefix(comment, "@name"):
+					request.name = strings.TrimSpace(strings.TrimPrefix(comment, "@name"))
+				case strings.HasPrefix(comment, "@tags"):
+					for _, tag := range strings.Split(strings.TrimPrefix(comment, "@tags"), ",") {
+						if tag = strings.TrimSpace(tag); tag != "" {
+							request.tags = append(request.tags, tag)
+						}
+					}
+				case strings.HasPrefix(comment, "@"):
+					// 其他指令（如 @no-redirect）不影响任务
+				case request.comment == "" && comment != "":
+					request.comment = comment
+				}
+				continue
+			}
+			if match := httpFileVariablePattern.FindStringSubmatch(trimmed); match != nil {
+				variables = append(variables, [2]string{match[1], strings.TrimSpace(match[2])})
+				continue
+			}
+
+			request.method, request.url = parseHTTPRequestLine(trimmed)
+			state = stateHeaders
+
+		case stateHeaders:
+			if trimmed == "" {
+				state = stateBody
+				continue
+			}
+			if strings.HasPrefix(trimmed, "?") || strings.HasPrefix(trimmed, "&") {
+				// 多行查询参数
+				request.url += trimmed
+				continue
+			}
+			if _, ok := httpFileComment(trimmed); ok {
+				continue
+			}
+			key, value, found := strings.Cut(trimmed, ":")
+			if !found {
+				report.warn("请求 '%s %s' 中无法识别的行：%s", request.method, request.url, trimmed)
+				continue
+			}
+			request.headers = append(request.headers, [2]string{strings.TrimSpace(key), strings.TrimSpace(value)})
+
+		case stateBody:
+			if skipBody {
+				continue
+			}
+			if len(request.body) == 0 && trimmed == "" {
+				continue
+			}
+			if strings.HasPrefix(trimmed, "> ") || strings.HasPrefix(trimmed, "<> ") {
+				report.warn("请求 '%s %s' 的响应处理脚本或响应引用未转换", request.method, request.url)
+				skipBody = true
+				continue
+			}
+			if len(request.body) == 0 && strings.HasPrefix(trimmed, "< ") {
+				report.warn("请求 '%s %s' 的请求体引用了外部文件 '%s'，未转换", request.method, request.url, strings.TrimSpace(trimmed[2:]))
+				skipBody = true
+				continue
+			}
+			request.body = append(request.body, line)
+		}
+	}
+	finish()
+
+	return requests, variables
+}
+
+// parseHTTPRequestLine 解析请求行（METHOD URL [HTTP/版本]），省略方法时为GET
+func parseHTTPRequestLine(line string) (string, string) {
+	method := "GET"
+	if first, rest, found := strings.Cut(line, " "); found && httpFileMethods[strings.ToUpper(first)] {
+		method = strings.ToUpper(first)
+		line = strings.TrimSpace(rest)
+	}
+
+	if index := strings.LastIndex(line, " HTTP/"); index >= 0 {
+		line = strings.TrimSpace(line[:index])
+	}
+	return method, line
+}
+
+// httpFileComment 判断是否为注释行（# 或 //），返回注释内容
+func httpFileComment(line string) (string, bool) {
+	if strings.HasPrefix(line, "#") {
+		return strings.TrimSpace(strings.TrimLeft(line, "#")), true
+	}
+	if strings.HasPrefix(line, "//") {
+		return strings.TrimSpace(strings.TrimPrefix(line, "//")), true
+	}
+	return "", false
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// multipart表单导入时使用的固定分隔符
+const multipartFormBoundary = "----HTTPTaskRunnerFormBoundary7MA4YWxkTrZu0gW"
+
+// multipartContentType 导入的multipart表单使用的Content-Type
+const multipartContentType = "multipart/form-data; boundary=" + multipartFormBoundary
+
+// TaskSource 任务的导入来源，重新导入同一来源时用于匹配已有任务
+type TaskSource struct {
+	Type     string `json:"type"`     // 来源类型: openapi
+	Path     string `json:"path"`     // 来源文件的绝对路径
+	Key      string `json:"key"`      // 来源中的条目标识（如 "GET /users/{id}"）
+	Checksum string `json:"checksum"` // 上次导入时生成内容的校验和，用于判断来源是否变化
+}
+
+// ImportReport 导入结果报告
+type ImportReport struct {
+	Source            string   `json:"source"`            // 导入来源（文件路径）
+	ImportedTasks     int      `json:"importedTasks"`     // 导入的任务数
+	UpdatedTasks      int      `json:"updatedTasks"`      // 更新的任务数
+	TaskIDs           []string `json:"taskIds"`           // 导入或更新的任务ID
+	ImportedVariables int      `json:"importedVariables"` // 导入的环境变量数
+	Warnings          []string `json:"warnings"`          // 未能转换的脚本或功能
+	Message           string   `json:"message"`           // 结果描述
+	Error             string   `json:"error"`             // 错误信息
+}
+
+// newImportReport 创建导入报告
+func newImportReport(source string) ImportReport {
+	return ImportReport{
+		Source:   source,
+		TaskIDs:  []string{},
+		Warnings: []string{},
+	}
+}
+
+// warn 记录一条未能转换的内容
+func (r *ImportReport) warn(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// fail 记录错误并返回报告
+func (r ImportReport) fail(format string, args ...interface{}) ImportReport {
+	r.Error = fmt.Sprintf(format, args...)
+	return r
+}
+
+// readImportFile 读取导入文件，path 为空时弹出打开对话框让用户选择
+func (a *App) readImportFile(path, title, filterName, pattern string) (string, []byte, error) {
+	if path == "" {
+		if a.ctx == nil {
+			return "", nil, fmt.Errorf("未指定导入文件")
+		}
+
+		selected, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+			Title: title,
+			Filters: []runtime.FileFilter{
+				{DisplayName: filterName, Pattern: pattern},
+			},
+		})
+		if err != nil {
+			return "", nil, fmt.Errorf("打开文件对话框失败：%v", err)
+		}
+		if selected == "" {
+			return "", nil, fmt.Errorf("已取消导入")
+		}
+		path = selected
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return path, nil, fmt.Errorf("读取文件失败：%v", err)
+	}
+
+	return path, data, nil
+}
+
+// buildMultipartBody 根据文本字段构建multipart/form-data请求体
+func buildMultipartBody(fields [][2]string) string {
+	var builder strings.Builder
+	for _, field := range fields {
+		builder.WriteString("--" + multipartFormBoundary + "\r\n")
+		builder.WriteString(fmt.Sprintf("Content-Disposition: form-data; name=\"%s\"\r\n\r\n", field[0]))
+		builder.WriteString(field[1] + "\r\n")
+	}
+	builder.WriteString("--" + multipartFormBoundary + "--\r\n")
+	return builder.String()
+}
+
+// headersToText 将有序的header列表转换为HeadersText格式
+func headersToText(headers [][2]string) string {
+	lines := make([]string, 0, len(headers))
+	for _, header := range headers {
+		lines = append(lines, header[0]+": "+header[1])
+	}
+	return strings.Join(lines, "\n")
+}
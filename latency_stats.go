@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// 延迟直方图的桶上限（毫秒），最后一个桶包含所有更大的值
+var latencyHistogramBounds = []int64{50, 100, 200, 300, 500, 1000, 2000, 5000, 10000, 30000}
+
+// LatencyBucket 延迟直方图桶
+type LatencyBucket struct {
+	UpperBound int64  `json:"upperBound"` // 桶上限(毫秒)，-1 表示无上限
+	Label      string `json:"label"`      // 显示标签
+	Count      int    `json:"count"`      // 落入该桶的请求数
+}
+
+// ThroughputPoint 吞吐量时间点（1秒一个桶）
+type ThroughputPoint struct {
+	Second       int    `json:"second"`       // 相对执行开始的秒数
+	Timestamp    string `json:"timestamp"`    // 该秒对应的时间
+	Requests     int    `json:"requests"`     // 完成的请求数
+	SuccessCount int    `json:"successCount"` // 成功数
+	FailedCount  int    `json:"failedCount"`  // 失败数
+}
+
+// RunStats 单次执行的延迟统计
+type RunStats struct {
+	SampleCount int               `json:"sampleCount"` // 参与统计的请求数
+	Min         int64             `json:"min"`         // 最小响应时间(毫秒)
+	Max         int64             `json:"max"`         // 最大响应时间(毫秒)
+	Mean        float64           `json:"mean"`        // 平均响应时间(毫秒)
+	StdDev      float64           `json:"stdDev"`      // 标准差(毫秒)
+	P50         int64             `json:"p50"`         // 50分位(毫秒)
+	P90         int64             `json:"p90"`         // 90分位(毫秒)
+	P95         int64             `json:"p95"`         // 95分位(毫秒)
+	P99         int64             `json:"p99"`         // 99分位(毫秒)
+	Histogram   []LatencyBucket   `json:"histogram"`   // 延迟直方图
+	Throughput  []ThroughputPoint `json:"throughput"`  // 每秒吞吐量
+	AvgRPS      float64           `json:"avgRps"`      // 平均每秒请求数
+}
+
+// GetExecutionStats 获取执行的延迟统计（旧日志没有持久化统计时即时计算）
+func (a *App) GetExecutionStats(taskLogID string) *RunStats {
+	a.logMutex.RLock()
+	executionLog, exists := a.executionLogs[taskLogID]
+	a.logMutex.RUnlock()
+
+	if !exists {
+		return nil
+	}
+
+	if executionLog.Stats != nil {
+		return executionLog.Stats
+	}
+
+	return computeRunStats(executionLog.DetailedLogs)
+}
+
+// computeRunStats 根据详细日志计算延迟统计
+func computeRunStats(detailedLogs []DetailedLogEntry) *RunStats {
+	stats := &RunStats{
+		Histogram:  newLatencyHistogram(),
+		Throughput: []ThroughputPoint{},
+	}
+
+	if len(detailedLogs) == 0 {
+		return stats
+	}
+
+	latencies := make([]int64, 0, len(detailedLogs))
+	var sum float64
+	for _, entry := range detailedLogs {
+		latencies = append(latencies, entry.ResponseTime)
+		sum += float64(entry.ResponseTime)
+
+		for i := range stats.Histogram {
+			if stats.Histogram[i].UpperBound < 0 || entry.ResponseTime <= stats.Histogram[i].UpperBound {
+				stats.Histogram[i].Count++
+				break
+			}
+		}
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	stats.SampleCount = len(latencies)
+	stats.Min = latencies[0]
+	stats.Max = latencies[len(latencies)-1]
+	stats.Mean = sum / float64(len(latencies))
+
+	var variance float64
+	for _, latency := range latencies {
+		diff := float64(latency) - stats.Mean
+		variance += diff * diff
+	}
+	stats.StdDev = math.Sqrt(variance / float64(len(latencies)))
+
+	stats.P50 = latencyPercentile(latencies, 50)
+	stats.P90 = latencyPercentile(latencies, 90)
+	stats.P95 = latencyPercentile(latencies, 95)
+	stats.P99 = latencyPercentile(latencies, 99)
+
+	stats.Throughput = computeThroughput(detailedLogs)
+	if len(stats.Throughput) > 0 {
+		stats.AvgRPS = float64(stats.SampleCount) / float64(len(stats.Throughput))
+	}
+
+	return stats
+}
+
+// latencyPercentile 使用最近秩法计算分位数（latencies需已升序排列）
+func latencyPercentile(latencies []int64, percentile float64) int64 {
+	if len(latencies) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(percentile / 100 * float64(len(latencies))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(latencies) {
+		rank = len(latencies)
+	}
+
+	return latencies[rank-1]
+}
+
+// newLatencyHistogram 创建空的延迟直方图
+func newLatencyHistogram() []LatencyBucket {
+	buckets := make([]LatencyBucket, 0, len(latencyHistogramBounds)+1)
+	var lower int64
+	for _, bound := range latencyHistogramBounds {
+		buckets = append(buckets, LatencyBucket{
+			UpperBound: bound,
+			Label:      formatLatencyRange(lower, bound),
+		})
+		lower = bound
+	}
+	buckets = append(buckets, LatencyBucket{
+		UpperBound: -1,
+		Label:      formatLatencyRange(lower, -1),
+	})
+
+	return buckets
+}
+
+// formatLatencyRange 生成直方图桶的显示标签
+func formatLatencyRange(lower, upper int64) string {
+	if upper < 0 {
+		return ">" + formatLatency(lower)
+	}
+	return formatLatency(lower) + "-" + formatLatency(upper)
+}
+
+// formatLatency 格式化毫秒值
+func formatLatency(ms int64) string {
+	if ms >= 1000 && ms%1000 == 0 {
+		return fmt.Sprintf("%ds", ms/1000)
+	}
+	return fmt.Sprintf("%dms", ms)
+}
+
+// computeThroughput 按1秒分桶统计吞吐量
+func computeThroughput(detailedLogs []DetailedLogEntry) []ThroughputPoint {
+	var start, end time.Time
+	times := make([]time.Time, len(detailedLogs))
+	for i, entry := range detailedLogs {
+		t, err := time.ParseInLocation("2006-01-02 15:04:05", entry.Timestamp, time.Local)
+		if err != nil {
+			continue
+		}
+		times[i] = t
+		if start.IsZero() || t.Before(start) {
+			start = t
+		}
+		if end.IsZero() || t.After(end) {
+			end = t
+		}
+	}
+
+	if start.IsZero() {
+		return []ThroughputPoint{}
+	}
+
+	seconds := int(end.Sub(start)/time.Second) + 1
+	points := make([]ThroughputPoint, seconds)
+	for i := range points {
+		points[i].Second = i
+		points[i].Timestamp = start.Add(time.Duration(i) * time.Second).Format("2006-01-02 15:04:05")
+	}
+
+	for i, entry := range detailedLogs {
+		if times[i].IsZero() {
+			continue
+		}
+		point := &points[int(times[i].Sub(start)/time.Second)]
+		point.Requests++
+		if entry.Success {
+			point.SuccessCount++
+		} else {
+			point.FailedCount++
+		}
+	}
+
+	return points
+}
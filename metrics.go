@@ -0,0 +1,468 @@
+package main
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// latencyBuckets 响应时间直方图的桶上界（秒）
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// MetricsSettings Prometheus指标接口配置
+type MetricsSettings struct {
+	Enabled bool   `json:"enabled"` // 是否启用
+	Address string `json:"address"` // 监听地址（如 127.0.0.1:9477，供其他主机抓取时使用 0.0.0.0:9477）
+	Token   string `json:"token"`   // 访问令牌（为空时不校验）
+}
+
+// MetricsStatus 指标接口运行状态
+type MetricsStatus struct {
+	Enabled bool   `json:"enabled"`
+	Running bool   `json:"running"`
+	Address string `json:"address"` // 抓取地址，如 http://127.0.0.1:9477/metrics
+	Error   string `json:"error"`   // 启动失败的原因
+}
+
+// requestMetricKey 请求计数的标签
+type requestMetricKey struct {
+	taskID    string
+	status    string // success, failed
+	errorType string // none, network, parsing, condition, http
+}
+
+// runMetricKey 执行计数的标签
+type runMetricKey struct {
+	taskID  string
+	status  string // success, partial, failed
+	trigger string
+}
+
+// latencyHistogram 响应时间直方图
+type latencyHistogram struct {
+	buckets []uint64 // 各桶的累计计数（与 latencyBuckets 对应）
+	sum     float64
+	count   uint64
+}
+
+// taskMetrics 单个任务的指标
+type taskMetrics struct {
+	latency      latencyHistogram
+	lastRun      int64 // 最近一次执行结束时间
+	lastSuccess  int64 // 最近一次成功执行结束时间
+	lastDuration int64 // 最近一次执行时长（秒）
+}
+
+// runMetrics 执行和请求指标（进程内累计，重启后计数器归零）
+type runMetrics struct {
+	mutex    sync.Mutex
+	requests map[requestMetricKey]uint64
+	runs     map[runMetricKey]uint64
+	tasks    map[string]*taskMetrics
+	seeded   bool // 是否已从执行历史恢复时间戳
+}
+
+// newRunMetrics 创建指标收集器
+func newRunMetrics() *runMetrics {
+	return &runMetrics{
+		requests: make(map[requestMetricKey]uint64),
+		runs:     make(map[runMetricKey]uint64),
+		tasks:    make(map[string]*taskMetrics),
+	}
+}
+
+// task 获取任务的指标（调用方持有锁）
+func (m *runMetrics) task(taskID string) *taskMetrics {
+	metrics, exists := m.tasks[taskID]
+	if !exists {
+		metrics = &taskMetrics{latency: latencyHistogram{buckets: make([]uint64, len(latencyBuckets))}}
+		m.tasks[taskID] = metrics
+	}
+	return metrics
+}
+
+// sortedTaskIDs 获取按字母排序的任务ID（调用方持有锁）
+func (m *runMetrics) sortedTaskIDs() []string {
+	taskIDs := make([]string, 0, len(m.tasks))
+	for taskID := range m.tasks {
+		taskIDs = append(taskIDs, taskID)
+	}
+	sort.Strings(taskIDs)
+	return taskIDs
+}
+
+// observeRequest 记录单个请求的结果和响应时间
+func (m *runMetrics) observeRequest(taskID string, detailLog DetailedLogEntry) {
+	status := "failed"
+	if detailLog.Success {
+		status = "success"
+	}
+	errorType := detailLog.ErrorType
+	if errorType == "" {
+		errorType = "none"
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.requests[requestMetricKey{taskID: taskID, status: status, errorType: errorType}]++
+
+	histogram := &m.task(taskID).latency
+	seconds := float64(detailLog.ResponseTime) / 1000
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			histogram.buckets[i]++
+		}
+	}
+	histogram.sum += seconds
+	histogram.count++
+}
+
+// observeRun 记录一次执行的结果
+func (m *runMetrics) observeRun(taskID, status, trigger string, duration int64) {
+	now := time.Now().Unix()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.runs[runMetricKey{taskID: taskID, status: status, trigger: trigger}]++
+	metrics := m.task(taskID)
+	metrics.lastRun = now
+	metrics.lastDuration = duration
+	if status == "success" {
+		metrics.lastSuccess = now
+	}
+}
+
+// removeTask 删除任务的全部指标（任务删除后不再输出）
+func (m *runMetrics) removeTask(taskID string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for key := range m.requests {
+		if key.taskID == taskID {
+			delete(m.requests, key)
+		}
+	}
+	for key := range m.runs {
+		if key.taskID == taskID {
+			delete(m.runs, key)
+		}
+	}
+	delete(m.tasks, taskID)
+}
+
+// pruneMetrics 删除已不存在的任务的指标（守护进程合并其他进程删除的任务后调用）
+func (a *App) pruneMetrics() {
+	a.metrics.mutex.Lock()
+	taskIDs := a.metrics.sortedTaskIDs()
+	a.metrics.mutex.Unlock()
+
+	a.cacheMutex.RLock()
+	var removed []string
+	for _, taskID := range taskIDs {
+		if _, exists := a.tasksCache[taskID]; !exists {
+			removed = append(removed, taskID)
+		}
+	}
+	a.cacheMutex.RUnlock()
+
+	for _, taskID := range removed {
+		a.metrics.removeTask(taskID)
+	}
+}
+
+// GetMetricsStatus 获取指标接口状态
+func (a *App) GetMetricsStatus() MetricsStatus {
+	settings := a.getSettings().Metrics
+	running, err := a.metricsService.status()
+
+	return MetricsStatus{
+		Enabled: settings.Enabled,
+		Running: running,
+		Address: fmt.Sprintf("http://%s/metrics", settings.Address),
+		Error:   err,
+	}
+}
+
+// defaultMetricsSettings 默认指标接口配置（默认关闭）
+func defaultMetricsSettings() MetricsSettings {
+	return MetricsSettings{
+		Enabled: false,
+		Address: "127.0.0.1:9477",
+	}
+}
+
+// validate 校验指标接口配置
+func (s MetricsSettings) validate() error {
+	if _, port, err := net.SplitHostPort(s.Address); err != nil || port == "" {
+		return fmt.Errorf("指标接口监听地址无效：%s", s.Address)
+	}
+	return nil
+}
+
+// startMetricsServer 按设置启动指标接口（守护进程运行时桌面程序不启动，指标只由执行定时任务的进程提供）
+func (a *App) startMetricsServer() {
+	settings := a.getSettings().Metrics
+	enabled := settings.Enabled && !a.schedulingDelegated()
+	if enabled {
+		a.seedMetricsFromHistory()
+	}
+	a.metricsService.start(enabled, settings.Address, a.metricsHandler)
+}
+
+// handoffMetricsServer 跟随调度交接指标接口：守护进程运行时桌面程序停止指标接口，守护进程退出后重新启动，
+// 守护进程启动时端口仍被桌面程序占用导致的失败也在这里重试（当前进程未提供指标接口时忽略）
+func (a *App) handoffMetricsServer(delegated bool) {
+	if !a.metricsService.isActive() || !a.getSettings().Metrics.Enabled {
+		return
+	}
+
+	running, _ := a.metricsService.status()
+	if delegated && running {
+		a.metricsService.stop()
+	} else if !delegated && !running {
+		a.startMetricsServer()
+	}
+}
+
+// reloadMetricsServer 设置变化后重启指标接口（当前进程未提供指标接口时忽略）
+func (a *App) reloadMetricsServer() {
+	if !a.metricsService.isActive() {
+		return
+	}
+
+	a.metricsService.stop()
+	a.startMetricsServer()
+}
+
+// seedMetricsFromHistory 从执行历史恢复各任务最近一次执行和成功的时间，避免重启后告警误报
+func (a *App) seedMetricsFromHistory() {
+	a.metrics.mutex.Lock()
+	seeded := a.metrics.seeded
+	a.metrics.seeded = true
+	a.metrics.mutex.Unlock()
+	if seeded {
+		return
+	}
+
+	records, err := a.dbGetLastRunTimes()
+	if err != nil {
+		fmt.Printf("读取执行历史失败: %v\n", err)
+		return
+	}
+
+	// 只恢复仍存在的任务
+	a.cacheMutex.RLock()
+	existing := make(map[string]bool, len(a.tasksCache))
+	for taskID := range a.tasksCache {
+		existing[taskID] = true
+	}
+	a.cacheMutex.RUnlock()
+
+	a.metrics.mutex.Lock()
+	defer a.metrics.mutex.Unlock()
+
+	for _, record := range records {
+		if !existing[record.TaskID] {
+			continue
+		}
+		metrics := a.metrics.task(record.TaskID)
+		finishedAt := record.StartedAt + record.Duration
+		if finishedAt > metrics.lastRun {
+			metrics.lastRun = finishedAt
+			metrics.lastDuration = record.Duration
+		}
+		if record.Status == "success" && finishedAt > metrics.lastSuccess {
+			metrics.lastSuccess = finishedAt
+		}
+	}
+}
+
+// dbGetLastRunTimes 查询每个任务各状态最近一次执行（只填充任务ID、状态、开始时间和时长）
+func (a *App) dbGetLastRunTimes() ([]RunRecord, error) {
+	a.dbMutex.RLock()
+	defer a.dbMutex.RUnlock()
+
+	query := `
+	SELECT task_id, status, started_at, duration
+	FROM run_history
+	WHERE (task_id, status, started_at) IN (
+		SELECT task_id, status, MAX(started_at) FROM run_history GROUP BY task_id, status
+	)
+	`
+
+	rows, err := a.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []RunRecord
+	for rows.Next() {
+		var record RunRecord
+		if err := rows.Scan(&record.TaskID, &record.Status, &record.StartedAt, &record.Duration); err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+// metricsHandler 指标接口路由
+func (a *App) metricsHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		if token := a.getSettings().Metrics.Token; token != "" {
+			provided := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Write([]byte(a.renderMetrics()))
+	})
+	return mux
+}
+
+// renderMetrics 按Prometheus文本格式输出指标
+func (a *App) renderMetrics() string {
+	taskNames := make(map[string]string)
+	a.cacheMutex.RLock()
+	for id, task := range a.tasksCache {
+		taskNames[id] = task.Name
+	}
+	taskCount := len(a.tasksCache)
+	a.cacheMutex.RUnlock()
+
+	a.taskMutex.RLock()
+	runningCount := len(a.runningTasks)
+	a.taskMutex.RUnlock()
+
+	var localSchedules, delegatedSchedules int
+	a.cronMutex.RLock()
+	for _, entryID := range a.cronJobs {
+		if entryID == delegatedEntryID {
+			delegatedSchedules++
+		} else {
+			localSchedules++
+		}
+	}
+	a.cronMutex.RUnlock()
+
+	taskLabels := func(taskID string) string {
+		name, exists := taskNames[taskID]
+		if !exists {
+			name = taskID
+		}
+		return fmt.Sprintf(`task_id="%s",task_name="%s"`, escapeMetricLabel(taskID), escapeMetricLabel(name))
+	}
+
+	var b strings.Builder
+	writeMetricHeader(&b, "httptaskrunner_tasks", "gauge", "任务总数")
+	fmt.Fprintf(&b, "httptaskrunner_tasks %d\n", taskCount)
+	writeMetricHeader(&b, "httptaskrunner_running_tasks", "gauge", "正在执行的任务数")
+	fmt.Fprintf(&b, "httptaskrunner_running_tasks %d\n", runningCount)
+	writeMetricHeader(&b, "httptaskrunner_scheduled_tasks", "gauge", "已启用定时调度的任务数（mode=daemon 表示由守护进程执行）")
+	fmt.Fprintf(&b, "httptaskrunner_scheduled_tasks{mode=\"local\"} %d\n", localSchedules)
+	fmt.Fprintf(&b, "httptaskrunner_scheduled_tasks{mode=\"daemon\"} %d\n", delegatedSchedules)
+
+	m := a.metrics
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	writeMetricHeader(&b, "httptaskrunner_requests_total", "counter", "请求数（按任务、结果和错误类型）")
+	requestKeys := make([]requestMetricKey, 0, len(m.requests))
+	for key := range m.requests {
+		requestKeys = append(requestKeys, key)
+	}
+	sort.Slice(requestKeys, func(i, j int) bool {
+		if requestKeys[i].taskID != requestKeys[j].taskID {
+			return requestKeys[i].taskID < requestKeys[j].taskID
+		}
+		if requestKeys[i].status != requestKeys[j].status {
+			return requestKeys[i].status < requestKeys[j].status
+		}
+		return requestKeys[i].errorType < requestKeys[j].errorType
+	})
+	for _, key := range requestKeys {
+		fmt.Fprintf(&b, "httptaskrunner_requests_total{%s,status=\"%s\",error_type=\"%s\"} %d\n",
+			taskLabels(key.taskID), key.status, escapeMetricLabel(key.errorType), m.requests[key])
+	}
+
+	writeMetricHeader(&b, "httptaskrunner_request_duration_seconds", "histogram", "请求响应时间")
+	taskIDs := m.sortedTaskIDs()
+	for _, taskID := range taskIDs {
+		histogram := m.tasks[taskID].latency
+		if histogram.count == 0 {
+			continue
+		}
+		labels := taskLabels(taskID)
+		for i, bound := range latencyBuckets {
+			fmt.Fprintf(&b, "httptaskrunner_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n",
+				labels, strconv.FormatFloat(bound, 'g', -1, 64), histogram.buckets[i])
+		}
+		fmt.Fprintf(&b, "httptaskrunner_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, histogram.count)
+		fmt.Fprintf(&b, "httptaskrunner_request_duration_seconds_sum{%s} %s\n", labels, strconv.FormatFloat(histogram.sum, 'g', -1, 64))
+		fmt.Fprintf(&b, "httptaskrunner_request_duration_seconds_count{%s} %d\n", labels, histogram.count)
+	}
+
+	writeMetricHeader(&b, "httptaskrunner_runs_total", "counter", "任务执行次数（按任务、结果和触发方式）")
+	runKeys := make([]runMetricKey, 0, len(m.runs))
+	for key := range m.runs {
+		runKeys = append(runKeys, key)
+	}
+	sort.Slice(runKeys, func(i, j int) bool {
+		if runKeys[i].taskID != runKeys[j].taskID {
+			return runKeys[i].taskID < runKeys[j].taskID
+		}
+		if runKeys[i].status != runKeys[j].status {
+			return runKeys[i].status < runKeys[j].status
+		}
+		return runKeys[i].trigger < runKeys[j].trigger
+	})
+	for _, key := range runKeys {
+		fmt.Fprintf(&b, "httptaskrunner_runs_total{%s,status=\"%s\",trigger=\"%s\"} %d\n",
+			taskLabels(key.taskID), key.status, escapeMetricLabel(key.trigger), m.runs[key])
+	}
+
+	writeMetricHeader(&b, "httptaskrunner_task_last_run_timestamp_seconds", "gauge", "最近一次执行结束的时间")
+	for _, taskID := range taskIDs {
+		if lastRun := m.tasks[taskID].lastRun; lastRun > 0 {
+			fmt.Fprintf(&b, "httptaskrunner_task_last_run_timestamp_seconds{%s} %d\n", taskLabels(taskID), lastRun)
+		}
+	}
+	writeMetricHeader(&b, "httptaskrunner_task_last_success_timestamp_seconds", "gauge", "最近一次执行成功的时间")
+	for _, taskID := range taskIDs {
+		if lastSuccess := m.tasks[taskID].lastSuccess; lastSuccess > 0 {
+			fmt.Fprintf(&b, "httptaskrunner_task_last_success_timestamp_seconds{%s} %d\n", taskLabels(taskID), lastSuccess)
+		}
+	}
+	writeMetricHeader(&b, "httptaskrunner_task_last_run_duration_seconds", "gauge", "最近一次执行的时长")
+	for _, taskID := range taskIDs {
+		if metrics := m.tasks[taskID]; metrics.lastRun > 0 {
+			fmt.Fprintf(&b, "httptaskrunner_task_last_run_duration_seconds{%s} %d\n", taskLabels(taskID), metrics.lastDuration)
+		}
+	}
+
+	return b.String()
+}
+
+// writeMetricHeader 输出指标的 HELP 和 TYPE 行
+func writeMetricHeader(b *strings.Builder, name, metricType, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+// escapeMetricLabel 转义标签值中的反斜杠、引号和换行
+func escapeMetricLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
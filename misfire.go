@@ -0,0 +1,308 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// 错过定时执行后的处理方式
+const (
+	MisfireIgnore = "ignore" // 忽略错过的执行
+	MisfireOnce   = "once"   // 补偿执行一次
+	MisfireAll    = "all"    // 补偿所有错过的执行（不超过上限）
+)
+
+// 补偿执行次数的默认值和上限
+const (
+	defaultMisfireLimit = 10
+	maxMisfireLimit     = 100
+)
+
+// maxMissedRunCount 统计错过的执行时最多检查的触发次数（超过时触发记录中注明次数不完整）
+const maxMissedRunCount = 100000
+
+// scheduleFireFlushInterval 定时触发时间写入数据库的最短间隔，每次触发只更新内存，交接调度和退出时写入
+const scheduleFireFlushInterval = daemonHeartbeatInterval
+
+// missedRuns 错过的执行
+type missedRuns struct {
+	count     int         // 错过的次数
+	truncated bool        // 超过 maxMissedRunCount 未统计完
+	first     time.Time   // 最早错过的执行时间
+	recent    []time.Time // 最近错过的执行时间（最多 maxMisfireLimit 个，用于补偿执行）
+}
+
+// countMissedRuns 统计 (from, until] 内错过的生效执行
+func countMissedRuns(cronExpr string, options ScheduleOptions, from, until time.Time) (missedRuns, error) {
+	var missed missedRuns
+	keepRecent := func(runTime time.Time) bool {
+		missed.recent = append(missed.recent, runTime)
+		if len(missed.recent) > maxMisfireLimit {
+			missed.recent = missed.recent[1:]
+		}
+		return true
+	}
+	completed, err := walkScheduleRunTimes(cronExpr, options, from, until, maxMissedRunCount, func(runTime time.Time) bool {
+		if missed.count == 0 {
+			missed.first = runTime
+		}
+		missed.count++
+		return keepRecent(runTime)
+	})
+	if err != nil || completed || len(missed.recent) < 2 {
+		missed.truncated = !completed
+		return missed, err
+	}
+
+	// 未统计完时按已统计的最近几次执行的间隔，从 until 之前重新计算最近错过的执行时间
+	missed.truncated = true
+	span := missed.recent[len(missed.recent)-1].Sub(missed.recent[0])
+	missed.recent = nil
+	_, err = walkScheduleRunTimes(cronExpr, options, until.Add(-2*span), until, maxMissedRunCount, keepRecent)
+	return missed, err
+}
+
+// scheduleFireState 任务最近一次定时触发的时间
+type scheduleFireState struct {
+	lastFire  time.Time
+	persisted time.Time // 已写入数据库的触发时间
+}
+
+// catchUpMissedRuns 注册调度后按错过处理方式补偿应用关闭期间错过的执行（应用启动、守护进程启动或接管调度时调用）
+func (a *App) catchUpMissedRuns(taskID string) {
+	a.cacheMutex.RLock()
+	task, exists := a.tasksCache[taskID]
+	a.cacheMutex.RUnlock()
+	if !exists {
+		return
+	}
+
+	now := time.Now()
+	lastFire, ok := a.lastScheduleFire(taskID)
+	a.recordScheduleFire(taskID, now)
+	if !ok {
+		return // 没有触发记录，从现在开始计算
+	}
+
+	options := a.getScheduleOptions(task)
+	missed, err := countMissedRuns(task.CronExpr, options, lastFire, now)
+	if err != nil || missed.count == 0 {
+		return
+	}
+	a.applyMisfirePolicy(task, options, missed, "应用关闭期间")
+}
+
+// checkMissedTicks 定时触发时检查上一次触发之后是否有被跳过的触发（例如系统休眠导致调度延迟），
+// 记录本次触发时间并返回本次触发的计划时间
+func (a *App) checkMissedTicks(task *Task, options ScheduleOptions, firedAt time.Time) time.Time {
+	scheduledAt := firedAt.Truncate(time.Second)
+
+	lastFire, ok := a.lastScheduleFire(task.ID)
+	a.recordScheduleTick(task.ID, firedAt)
+	if !ok {
+		return scheduledAt
+	}
+
+	// 调度延迟时调度器只执行最早到期的一次触发（即本次触发），之后到期的触发都被跳过
+	schedule, err := parseCronSchedule(task.CronExpr, options.TimeZone)
+	if err != nil {
+		return scheduledAt
+	}
+	if next := schedule.Next(lastFire); !next.IsZero() && !next.After(firedAt) {
+		scheduledAt = next
+	}
+
+	missed, err := countMissedRuns(task.CronExpr, options, scheduledAt, firedAt)
+	if err == nil && missed.count > 0 {
+		a.applyMisfirePolicy(task, options, missed, "调度延迟（如系统休眠）期间")
+	}
+	return scheduledAt
+}
+
+// applyMisfirePolicy 记录错过的执行，并按任务的处理方式在后台补偿最近的几次执行
+func (a *App) applyMisfirePolicy(task *Task, options ScheduleOptions, missed missedRuns, period string) {
+	runs := 0
+	switch options.Misfire {
+	case MisfireOnce:
+		runs = 1
+	case MisfireAll:
+		runs = len(missed.recent)
+		limit := options.MisfireLimit
+		if limit <= 0 {
+			limit = defaultMisfireLimit
+		}
+		if runs > limit {
+			runs = limit
+		}
+	}
+
+	count := fmt.Sprintf("%d次", missed.count)
+	if missed.truncated {
+		count = fmt.Sprintf("超过%d次", missed.count)
+	}
+	message := fmt.Sprintf("%s错过%s定时执行（最早 %s）", period, count, missed.first.Local().Format("2006-01-02 15:04:05"))
+	if runs == 0 {
+		message += "，已忽略"
+	} else {
+		message += fmt.Sprintf("，将补偿执行%d次", runs)
+	}
+	// 只写入触发记录，不写入任务日志，避免挤占执行日志的保留条数
+	a.addScheduleFire(task.ID, missed.first, time.Now(), "missed", message)
+
+	if runs > 0 {
+		go a.runCatchUp(task.ID, missed.recent[len(missed.recent)-runs:])
+	}
+}
+
+// runCatchUp 依次补偿错过的执行，每次等待任务的其他运行结束（任务被删除或应用关闭时停止）
+func (a *App) runCatchUp(taskID string, ticks []time.Time) {
+	for _, tick := range ticks {
+		var progress *TaskProgress
+		for {
+			// 在检查运行状态的同一次加锁中登记运行，避免与其他触发同时启动
+			a.taskMutex.Lock()
+			if _, running := a.runningTasks[taskID]; !running {
+				progress = a.addRunLocked(taskID)
+			}
+			a.taskMutex.Unlock()
+			if progress != nil {
+				break
+			}
+
+			select {
+			case <-time.After(time.Second):
+			case <-a.shutdown:
+				return
+			}
+		}
+
+		a.cacheMutex.RLock()
+		task, exists := a.tasksCache[taskID]
+		a.cacheMutex.RUnlock()
+		if !exists {
+			a.abandonRun(taskID, progress)
+			return
+		}
+
+		fireID := a.addScheduleFire(taskID, tick, time.Now(), "catchup", "")
+		a.runScheduledTask(task, TriggerCatchUp, fireID, progress)
+	}
+}
+
+// lastScheduleFire 获取任务最近一次定时触发的时间（内存中没有时从数据库读取）
+func (a *App) lastScheduleFire(taskID string) (time.Time, bool) {
+	a.fireMutex.Lock()
+	state, exists := a.fireStates[taskID]
+	a.fireMutex.Unlock()
+	if exists {
+		return state.lastFire, true
+	}
+
+	lastFire, ok := a.dbGetLastFireTime(taskID)
+	if ok {
+		a.fireMutex.Lock()
+		if _, exists := a.fireStates[taskID]; !exists {
+			a.fireStates[taskID] = &scheduleFireState{lastFire: lastFire, persisted: lastFire}
+		}
+		a.fireMutex.Unlock()
+	}
+	return lastFire, ok
+}
+
+// recordScheduleFire 保存任务最近一次定时触发的时间并立即写入数据库（启用调度、补偿检查等重置计算起点时使用）
+func (a *App) recordScheduleFire(taskID string, firedAt time.Time) {
+	a.fireMutex.Lock()
+	a.fireStates[taskID] = &scheduleFireState{lastFire: firedAt, persisted: firedAt}
+	a.fireMutex.Unlock()
+
+	if err := a.dbSetLastFireTime(taskID, firedAt); err != nil {
+		fmt.Printf("保存定时触发时间失败: %v\n", err)
+	}
+}
+
+// recordScheduleTick 记录一次定时触发，距上次写入数据库超过 scheduleFireFlushInterval 时才写入，
+// 避免高频调度每次触发都写数据库
+func (a *App) recordScheduleTick(taskID string, firedAt time.Time) {
+	a.fireMutex.Lock()
+	state, exists := a.fireStates[taskID]
+	if !exists {
+		state = &scheduleFireState{}
+		a.fireStates[taskID] = state
+	}
+	state.lastFire = firedAt
+	persist := firedAt.Sub(state.persisted) >= scheduleFireFlushInterval
+	if persist {
+		state.persisted = firedAt
+	}
+	a.fireMutex.Unlock()
+
+	if persist {
+		if err := a.dbSetLastFireTime(taskID, firedAt); err != nil {
+			fmt.Printf("保存定时触发时间失败: %v\n", err)
+		}
+	}
+}
+
+// flushScheduleFires 把尚未写入的定时触发时间写入数据库并清空内存中的记录
+// 交接调度和退出时调用，之后由其他进程更新的触发时间会重新从数据库读取
+func (a *App) flushScheduleFires() {
+	a.fireMutex.Lock()
+	states := a.fireStates
+	a.fireStates = make(map[string]*scheduleFireState)
+	a.fireMutex.Unlock()
+
+	for taskID, state := range states {
+		if !state.lastFire.After(state.persisted) {
+			continue
+		}
+		if err := a.dbSetLastFireTime(taskID, state.lastFire); err != nil {
+			fmt.Printf("保存定时触发时间失败: %v\n", err)
+		}
+	}
+}
+
+// clearScheduleFire 删除任务的定时触发时间（移除调度时调用）
+func (a *App) clearScheduleFire(taskID string) {
+	a.fireMutex.Lock()
+	delete(a.fireStates, taskID)
+	a.fireMutex.Unlock()
+
+	if err := a.dbDeleteScheduleState(taskID); err != nil {
+		fmt.Printf("删除定时触发记录失败: %v\n", err)
+	}
+}
+
+// dbGetLastFireTime 获取任务最近一次定时触发的时间
+func (a *App) dbGetLastFireTime(taskID string) (time.Time, bool) {
+	a.dbMutex.RLock()
+	defer a.dbMutex.RUnlock()
+
+	var lastFireAt int64
+	err := a.db.QueryRow("SELECT last_fire_at FROM schedule_state WHERE task_id = ?", taskID).Scan(&lastFireAt)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			fmt.Printf("读取定时触发时间失败: %v\n", err)
+		}
+		return time.Time{}, false
+	}
+	return time.Unix(lastFireAt, 0), true
+}
+
+// dbSetLastFireTime 保存任务最近一次定时触发的时间
+func (a *App) dbSetLastFireTime(taskID string, firedAt time.Time) error {
+	a.dbMutex.Lock()
+	defer a.dbMutex.Unlock()
+
+	_, err := a.db.Exec("INSERT OR REPLACE INTO schedule_state (task_id, last_fire_at) VALUES (?, ?)", taskID, firedAt.Unix())
+	return err
+}
+
+// dbDeleteScheduleState 删除任务的定时触发记录
+func (a *App) dbDeleteScheduleState(taskID string) error {
+	a.dbMutex.Lock()
+	defer a.dbMutex.Unlock()
+
+	_, err := a.db.Exec("DELETE FROM schedule_state WHERE task_id = ?", taskID)
+	return err
+}
@@ -0,0 +1,552 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 通知中列出的失败分组数量
+const maxNotificationFailureGroups = 3
+
+// NotificationRules 通知触发规则
+type NotificationRules struct {
+	OnFailure        bool    `json:"onFailure"`        // 执行失败（全部请求失败）
+	OnPartial        bool    `json:"onPartial"`        // 部分成功
+	OnRecovery       bool    `json:"onRecovery"`       // 上次失败/部分成功后恢复成功
+	SuccessRateBelow float64 `json:"successRateBelow"` // 成功率低于该百分比时通知（0表示不启用）
+}
+
+// NotificationChannel 通知渠道
+type NotificationChannel struct {
+	Name         string            `json:"name"`
+	Type         string            `json:"type"` // webhook, slack, dingtalk, wecom, feishu, email
+	Enabled      bool              `json:"enabled"`
+	URL          string            `json:"url"`          // Webhook或机器人地址
+	Headers      map[string]string `json:"headers"`      // 通用Webhook的附加请求头
+	BodyTemplate string            `json:"bodyTemplate"` // 通用Webhook的JSON模板（为空时发送完整的通知内容）
+	Secret       string            `json:"secret"`       // 钉钉/飞书机器人的加签密钥
+	Email        *EmailSettings    `json:"email"`        // 邮件渠道的SMTP配置
+	Rules        NotificationRules `json:"rules"`
+}
+
+// NotificationConfig 通知配置
+type NotificationConfig struct {
+	Channels []NotificationChannel `json:"channels"`
+}
+
+// RunNotification 一次执行的通知内容
+type RunNotification struct {
+	Event         string         `json:"event"` // failure, partial, recovery, threshold, test
+	EventText     string         `json:"eventText"`
+	TaskID        string         `json:"taskId"`
+	TaskName      string         `json:"taskName"`
+	TaskLogID     string         `json:"taskLogId"`
+	Trigger       string         `json:"trigger"`
+	Status        string         `json:"status"` // success, partial, failed
+	TotalRequests int            `json:"totalRequests"`
+	SuccessCount  int            `json:"successCount"`
+	FailedCount   int            `json:"failedCount"`
+	SuccessRate   float64        `json:"successRate"` // 百分比
+	Duration      int64          `json:"duration"`    // 秒
+	Summary       string         `json:"summary"`     // 执行摘要（与执行日志一致）
+	FinishedAt    string         `json:"finishedAt"`
+	FailureGroups []FailureGroup `json:"failureGroups"` // 主要失败原因
+}
+
+// notificationEventTexts 通知事件的显示文本
+var notificationEventTexts = map[string]string{
+	"failure":   "执行失败",
+	"partial":   "部分成功",
+	"recovery":  "恢复正常",
+	"threshold": "成功率低于阈值",
+	"test":      "测试通知",
+}
+
+// validate 校验通知配置
+func (c NotificationConfig) validate() error {
+	for i, channel := range c.Channels {
+		if err := channel.validate(); err != nil {
+			return fmt.Errorf("通知渠道 %d（%s）：%v", i+1, channel.Name, err)
+		}
+	}
+	return nil
+}
+
+// validate 校验通知渠道
+func (c NotificationChannel) validate() error {
+	switch c.Type {
+	case "webhook", "slack", "dingtalk", "wecom", "feishu":
+		if !c.Enabled && c.URL == "" {
+			// 导入工作区后等待重新填写地址的渠道
+			break
+		}
+		parsed, err := url.Parse(c.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("通知地址无效：%s", c.URL)
+		}
+	case "email":
+		if err := c.Email.validate(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("不支持的通知类型：%s", c.Type)
+	}
+
+	if c.Rules.SuccessRateBelow < 0 || c.Rules.SuccessRateBelow > 100 {
+		return fmt.Errorf("成功率阈值必须在0-100之间")
+	}
+
+	if c.Type == "webhook" && c.BodyTemplate != "" {
+		sample := renderNotificationTemplate(c.BodyTemplate, sampleRunNotification())
+		if !json.Valid([]byte(sample)) {
+			return fmt.Errorf("Webhook模板渲染后不是有效的JSON")
+		}
+	}
+	return nil
+}
+
+// withoutSecrets 返回不含通知地址、加签密钥和SMTP密码的副本（导出工作区时使用，持有这些值即可发送消息）
+func (c NotificationConfig) withoutSecrets() NotificationConfig {
+	if c.Channels == nil {
+		return c
+	}
+	channels := make([]NotificationChannel, len(c.Channels))
+	for i, channel := range c.Channels {
+		channel.URL = ""
+		channel.Secret = ""
+		if channel.Email != nil {
+			email := *channel.Email
+			email.Password = ""
+			channel.Email = &email
+		}
+		channels[i] = channel
+	}
+	return NotificationConfig{Channels: channels}
+}
+
+// restoreSecrets 导入工作区时从本地类型和名称相同的通知渠道恢复地址和密钥（按 locals 的顺序查找），
+// 找不到且缺少地址或SMTP密码的渠道被停用，返回需要提示用户的信息
+func (c NotificationConfig) restoreSecrets(locals ...NotificationConfig) (NotificationConfig, []string) {
+	if c.Channels == nil {
+		return c, nil
+	}
+	var warnings []string
+	channels := make([]NotificationChannel, len(c.Channels))
+	for i, channel := range c.Channels {
+		if channel.Email != nil {
+			email := *channel.Email
+			channel.Email = &email
+		}
+		if local, ok := findNotificationChannel(channel.Type, channel.Name, locals); ok {
+			if channel.URL == "" {
+				channel.URL = local.URL
+			}
+			if channel.Secret == "" {
+				channel.Secret = local.Secret
+			}
+			if channel.Email != nil && channel.Email.Password == "" && local.Email != nil {
+				channel.Email.Password = local.Email.Password
+			}
+		}
+
+		missing := false
+		if channel.Type == "email" {
+			missing = channel.Email != nil && channel.Email.Username != "" && channel.Email.Password == ""
+		} else {
+			missing = channel.URL == ""
+		}
+		if missing && channel.Enabled {
+			channel.Enabled = false
+			warnings = append(warnings, fmt.Sprintf("通知渠道 '%s' 的地址或密码未随工作区导出，已停用，请重新填写后启用", channel.Name))
+		}
+		channels[i] = channel
+	}
+	return NotificationConfig{Channels: channels}, warnings
+}
+
+// findNotificationChannel 查找类型和名称相同的通知渠道
+func findNotificationChannel(channelType, name string, configs []NotificationConfig) (NotificationChannel, bool) {
+	for _, config := range configs {
+		for _, channel := range config.Channels {
+			if channel.Type == channelType && channel.Name == name {
+				return channel, true
+			}
+		}
+	}
+	return NotificationChannel{}, false
+}
+
+// getNotificationConfig 获取任务生效的通知配置（任务未单独设置时使用全局配置）
+func (a *App) getNotificationConfig(taskID string) NotificationConfig {
+	a.cacheMutex.RLock()
+	task, exists := a.tasksCache[taskID]
+	var config *NotificationConfig
+	if exists {
+		config = task.Notifications
+	}
+	a.cacheMutex.RUnlock()
+
+	if config != nil {
+		return *config
+	}
+	return a.getSettings().Notifications
+}
+
+// SetTaskNotifications 设置任务的通知配置，configJson 为空时恢复使用全局配置
+func (a *App) SetTaskNotifications(taskID, configJson string) string {
+	var config *NotificationConfig
+	if configJson != "" {
+		config = &NotificationConfig{}
+		if err := json.Unmarshal([]byte(configJson), config); err != nil {
+			return fmt.Sprintf("数据格式错误：%v", err)
+		}
+		if err := config.validate(); err != nil {
+			return fmt.Sprintf("错误：%v", err)
+		}
+	}
+
+	a.cacheMutex.Lock()
+	defer a.cacheMutex.Unlock()
+
+	task, exists := a.tasksCache[taskID]
+	if !exists {
+		return "错误：任务不存在"
+	}
+
+	task.Notifications = config
+	task.UpdatedAt = time.Now().Unix()
+
+	// 保存到磁盘
+	if err := a.saveTasksToDisk(); err != nil {
+		return fmt.Sprintf("保存失败：%v", err)
+	}
+
+	if config == nil {
+		return fmt.Sprintf("任务 '%s' 已恢复使用全局通知配置", task.Name)
+	}
+	return fmt.Sprintf("任务 '%s' 的通知配置设置成功", task.Name)
+}
+
+// TestNotificationChannel 使用示例数据向通知渠道发送一条测试消息
+func (a *App) TestNotificationChannel(channelJson string) string {
+	var channel NotificationChannel
+	if err := json.Unmarshal([]byte(channelJson), &channel); err != nil {
+		return fmt.Sprintf("数据格式错误：%v", err)
+	}
+	if err := channel.validate(); err != nil {
+		return fmt.Sprintf("错误：%v", err)
+	}
+
+	if err := a.sendNotification(channel, sampleRunNotification()); err != nil {
+		return fmt.Sprintf("发送失败：%v", err)
+	}
+	return "测试通知发送成功"
+}
+
+// notifyRunFinished 执行结束后按通知规则在后台发送通知
+func (a *App) notifyRunFinished(task *Task, taskLogID, status, trigger string) {
+	config := a.getNotificationConfig(task.ID)
+	if len(config.Channels) == 0 {
+		return
+	}
+
+	a.logMutex.RLock()
+	executionLog, exists := a.executionLogs[taskLogID]
+	a.logMutex.RUnlock()
+	if !exists {
+		return
+	}
+
+	notification := RunNotification{
+		TaskID:        task.ID,
+		TaskName:      task.Name,
+		TaskLogID:     taskLogID,
+		Trigger:       trigger,
+		Status:        status,
+		TotalRequests: executionLog.TotalRequests,
+		SuccessCount:  executionLog.SuccessCount,
+		FailedCount:   executionLog.FailedCount,
+		Duration:      executionLog.Duration,
+		Summary:       executionLog.Summary,
+		FinishedAt:    time.Now().Format("2006-01-02 15:04:05"),
+	}
+	if notification.TotalRequests > 0 {
+		notification.SuccessRate = float64(notification.SuccessCount) / float64(notification.TotalRequests) * 100
+	}
+
+	previousStatus := ""
+	if status == "success" {
+		previousStatus = a.dbGetPreviousRunStatus(task.ID, taskLogID)
+	}
+
+	var failureGroups []FailureGroup
+	if notification.FailedCount > 0 {
+		failureGroups = a.GetRunFailureGroups(taskLogID).Groups
+		if len(failureGroups) > maxNotificationFailureGroups {
+			failureGroups = failureGroups[:maxNotificationFailureGroups]
+		}
+	}
+	notification.FailureGroups = failureGroups
+
+	for _, channel := range config.Channels {
+		if !channel.Enabled {
+			continue
+		}
+		event := channel.Rules.match(status, previousStatus, notification.SuccessRate)
+		if event == "" {
+			continue
+		}
+
+		channelNotification := notification
+		channelNotification.Event = event
+		channelNotification.EventText = notificationEventTexts[event]
+		channel := channel
+		// 与后台保存一起在退出前等待完成，避免命令行模式下通知丢失
+		a.saveInBackground(func() error {
+			if err := a.sendNotification(channel, channelNotification); err != nil {
+				fmt.Printf("发送通知 '%s' 失败: %v\n", channel.Name, err)
+			}
+			return nil
+		})
+	}
+}
+
+// match 判断执行结果是否满足通知规则，返回触发的事件（不满足时返回空字符串）
+func (r NotificationRules) match(status, previousStatus string, successRate float64) string {
+	switch {
+	case status == "failed" && r.OnFailure:
+		return "failure"
+	case status == "partial" && r.OnPartial:
+		return "partial"
+	case status == "success" && r.OnRecovery && (previousStatus == "failed" || previousStatus == "partial"):
+		return "recovery"
+	case r.SuccessRateBelow > 0 && successRate < r.SuccessRateBelow:
+		return "threshold"
+	}
+	return ""
+}
+
+// dbGetPreviousRunStatus 获取任务上一次执行的状态（没有记录时返回空字符串）
+func (a *App) dbGetPreviousRunStatus(taskID, taskLogID string) string {
+	a.dbMutex.RLock()
+	defer a.dbMutex.RUnlock()
+
+	query := `
+	SELECT status FROM run_history
+	WHERE task_id = ? AND task_log_id != ?
+	ORDER BY started_at DESC
+	LIMIT 1
+	`
+
+	var status string
+	if err := a.db.QueryRow(query, taskID, taskLogID).Scan(&status); err != nil {
+		return ""
+	}
+	return status
+}
+
+// sampleRunNotification 用于测试和校验模板的示例通知
+func sampleRunNotification() RunNotification {
+	return RunNotification{
+		Event:         "test",
+		EventText:     notificationEventTexts["test"],
+		TaskID:        "task_example",
+		TaskName:      "示例任务",
+		TaskLogID:     "task_example_log",
+		Trigger:       TriggerManual,
+		Status:        "partial",
+		TotalRequests: 10,
+		SuccessCount:  8,
+		FailedCount:   2,
+		SuccessRate:   80,
+		Duration:      3,
+		Summary:       "执行完成，成功率: 80.0%",
+		FinishedAt:    time.Now().Format("2006-01-02 15:04:05"),
+		FailureGroups: []FailureGroup{{Summary: "HTTP 503 Service Unavailable", Count: 2}},
+	}
+}
+
+// notificationText 生成通知的文本内容
+func notificationText(n RunNotification, markdown bool) string {
+	var b strings.Builder
+
+	title := fmt.Sprintf("【%s】任务 '%s' %s", AppName, n.TaskName, n.EventText)
+	if markdown {
+		fmt.Fprintf(&b, "### %s\n\n", title)
+	} else {
+		b.WriteString(title + "\n")
+	}
+
+	lines := []string{
+		fmt.Sprintf("结果: 成功 %d/%d（成功率 %.1f%%）", n.SuccessCount, n.TotalRequests, n.SuccessRate),
+		fmt.Sprintf("耗时: %d秒", n.Duration),
+		fmt.Sprintf("摘要: %s", n.Summary),
+		fmt.Sprintf("完成时间: %s", n.FinishedAt),
+		fmt.Sprintf("执行日志ID: %s", n.TaskLogID),
+	}
+	for _, line := range lines {
+		if markdown {
+			b.WriteString("- " + line + "\n")
+		} else {
+			b.WriteString(line + "\n")
+		}
+	}
+
+	if len(n.FailureGroups) > 0 {
+		if markdown {
+			b.WriteString("\n**主要失败原因**\n\n")
+		} else {
+			b.WriteString("主要失败原因:\n")
+		}
+		for _, group := range n.FailureGroups {
+			fmt.Fprintf(&b, "- %s（%d次）\n", group.Summary, group.Count)
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// renderNotificationTemplate 替换Webhook模板中的 {{字段}} 占位符（字符串值按JSON转义）
+func renderNotificationTemplate(template string, n RunNotification) string {
+	escape := func(value string) string {
+		data, _ := json.Marshal(value)
+		return string(data[1 : len(data)-1])
+	}
+
+	replacer := strings.NewReplacer(
+		"{{event}}", escape(n.Event),
+		"{{eventText}}", escape(n.EventText),
+		"{{taskId}}", escape(n.TaskID),
+		"{{taskName}}", escape(n.TaskName),
+		"{{taskLogId}}", escape(n.TaskLogID),
+		"{{trigger}}", escape(n.Trigger),
+		"{{status}}", escape(n.Status),
+		"{{summary}}", escape(n.Summary),
+		"{{finishedAt}}", escape(n.FinishedAt),
+		"{{message}}", escape(notificationText(n, false)),
+		"{{totalRequests}}", strconv.Itoa(n.TotalRequests),
+		"{{successCount}}", strconv.Itoa(n.SuccessCount),
+		"{{failedCount}}", strconv.Itoa(n.FailedCount),
+		"{{successRate}}", strconv.FormatFloat(n.SuccessRate, 'f', 1, 64),
+		"{{duration}}", strconv.FormatInt(n.Duration, 10),
+	)
+	return replacer.Replace(template)
+}
+
+// sendNotification 按渠道类型构建消息并发送
+func (a *App) sendNotification(channel NotificationChannel, n RunNotification) error {
+	targetURL := channel.URL
+	var payload interface{}
+
+	switch channel.Type {
+	case "webhook":
+		if channel.BodyTemplate != "" {
+			return postNotification(targetURL, []byte(renderNotificationTemplate(channel.BodyTemplate, n)), channel.Headers, false)
+		}
+		payload = n
+	case "slack":
+		payload = map[string]string{"text": notificationText(n, false)}
+	case "dingtalk":
+		if channel.Secret != "" {
+			timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+			sign := signNotification(channel.Secret, timestamp+"\n"+channel.Secret)
+			separator := "?"
+			if strings.Contains(targetURL, "?") {
+				separator = "&"
+			}
+			targetURL += separator + "timestamp=" + timestamp + "&sign=" + url.QueryEscape(sign)
+		}
+		payload = map[string]interface{}{
+			"msgtype": "markdown",
+			"markdown": map[string]string{
+				"title": fmt.Sprintf("任务 '%s' %s", n.TaskName, n.EventText),
+				"text":  notificationText(n, true),
+			},
+		}
+	case "wecom":
+		payload = map[string]interface{}{
+			"msgtype":  "markdown",
+			"markdown": map[string]string{"content": notificationText(n, true)},
+		}
+	case "feishu":
+		body := map[string]interface{}{
+			"msg_type": "text",
+			"content":  map[string]string{"text": notificationText(n, false)},
+		}
+		if channel.Secret != "" {
+			timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+			body["timestamp"] = timestamp
+			body["sign"] = signNotification(timestamp+"\n"+channel.Secret, "")
+		}
+		payload = body
+	case "email":
+		return a.sendEmailNotification(channel.Email, n)
+	default:
+		return fmt.Errorf("不支持的通知类型：%s", channel.Type)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return postNotification(targetURL, data, channel.Headers, channel.Type != "webhook")
+}
+
+// signNotification 计算机器人加签（HMAC-SHA256后Base64编码）
+// 钉钉以密钥为key签名 "timestamp\nsecret"，飞书以 "timestamp\nsecret" 为key签名空字符串
+func signNotification(key, message string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(message))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// postNotification 发送JSON请求，robot 为 true 时检查机器人接口的返回码
+func postNotification(targetURL string, body []byte, headers map[string]string, robot bool) error {
+	req, err := http.NewRequest("POST", targetURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	// 钉钉/企业微信返回 errcode，飞书返回 code，非0表示失败
+	var result struct {
+		ErrCode *int   `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+		Code    *int   `json:"code"`
+		Msg     string `json:"msg"`
+	}
+	if robot && json.Unmarshal(respBody, &result) == nil {
+		if result.ErrCode != nil && *result.ErrCode != 0 {
+			return fmt.Errorf("错误码 %d: %s", *result.ErrCode, result.ErrMsg)
+		}
+		if result.Code != nil && *result.Code != 0 {
+			return fmt.Errorf("错误码 %d: %s", *result.Code, result.Msg)
+		}
+	}
+	return nil
+}
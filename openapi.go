@@ -0,0 +1,737 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OpenAPI文档中按此顺序生成各HTTP方法的任务
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// 生成示例请求体时的最大嵌套深度（防止循环引用）
+const openAPIMaxSchemaDepth = 8
+
+// ImportOpenAPISpec 从OpenAPI 3或Swagger 2文档（JSON或YAML）生成任务，每个操作一个任务
+// 再次导入同一文件即重新同步：文档有变化的操作更新对应任务，新增的操作创建任务，已删除的操作只在报告中提示
+// path 为空时弹出文件选择对话框，baseURL 为空时使用文档中的服务器地址
+func (a *App) ImportOpenAPISpec(path, baseURL string) ImportReport {
+	path, data, err := a.readImportFile(path, "选择OpenAPI/Swagger文档", "OpenAPI文档 (*.json;*.yaml;*.yml)", "*.json;*.yaml;*.yml")
+	report := newImportReport(path)
+	if err != nil {
+		return report.fail("%v", err)
+	}
+	if absPath, err := filepath.Abs(path); err == nil {
+		path = absPath
+		report.Source = absPath
+	}
+
+	doc, err := parseOpenAPIDocument(data)
+	if err != nil {
+		return report.fail("%v", err)
+	}
+
+	converter := &openAPIConverter{app: a, doc: doc, report: &report}
+	if version, _ := doc["openapi"].(string); strings.HasPrefix(version, "3.") {
+		converter.version = 3
+	} else if version, _ := doc["swagger"].(string); version == "2.0" {
+		converter.version = 2
+	} else {
+		return report.fail("不支持的文档版本（仅支持OpenAPI 3.x和Swagger 2.0）")
+	}
+
+	if baseURL == "" {
+		baseURL = converter.baseURL()
+	}
+	converter.base = strings.TrimRight(baseURL, "/")
+
+	generated := converter.convert(path)
+	if len(generated) == 0 {
+		return report.fail("文档中没有可导入的操作")
+	}
+
+	if err := a.syncImportedTasks(&report, "openapi", path, generated); err != nil {
+		return report.fail("保存任务失败：%v", err)
+	}
+
+	title := stringValue(mapValue(doc["info"])["title"])
+	report.Message = fmt.Sprintf("从 '%s' 导入 %d 个任务、更新 %d 个任务，%d 条提示",
+		title, report.ImportedTasks, report.UpdatedTasks, len(report.Warnings))
+	return report
+}
+
+// syncImportedTasks 按来源合并导入的任务：来源条目已存在时更新（内容未变化则跳过，本地修改过的任务在报告中提示），不存在时新建
+func (a *App) syncImportedTasks(report *ImportReport, sourceType, sourcePath string, generated []*Task) error {
+	a.cacheMutex.Lock()
+
+	existing := make(map[string]*Task)
+	for _, task := range a.tasksCache {
+		if task.Source != nil && task.Source.Type == sourceType && task.Source.Path == sourcePath {
+			existing[task.Source.Key] = task
+		}
+	}
+
+	now := time.Now().Unix()
+	for _, task := range generated {
+		current, exists := existing[task.Source.Key]
+		if !exists {
+			a.tasksCache[task.ID] = task
+			report.ImportedTasks++
+			report.TaskIDs = append(report.TaskIDs, task.ID)
+			continue
+		}
+		delete(existing, task.Source.Key)
+
+		if current.Source.Checksum == task.Source.Checksum {
+			continue
+		}
+
+		// 生成字段与上次导入的校验和不一致说明在本地修改过，更新后本地修改会被覆盖
+		if importedTaskChecksum(current) != current.Source.Checksum {
+			report.warn("任务 '%s' 在本地修改过，已按来源中的 '%s' 覆盖", current.Name, task.Source.Key)
+		}
+
+		// 只更新来源生成的字段，执行次数、并发、定时等设置保持不变
+		// 更新副本后替换缓存中的任务，执行中的运行仍读取原任务
+		updated := *current
+		source := *current.Source
+		updated.Source = &source
+		updated.Name = task.Name
+		updated.URL = task.URL
+		updated.Method = task.Method
+		updated.Headers = task.Headers
+		updated.HeadersText = task.HeadersText
+		updated.Data = task.Data
+		updated.Tags = task.Tags
+		updated.SuccessCondition.StatusCodes = task.SuccessCondition.StatusCodes
+		updated.Source.Checksum = task.Source.Checksum
+		updated.UpdatedAt = now
+		a.tasksCache[current.ID] = &updated
+		report.UpdatedTasks++
+		report.TaskIDs = append(report.TaskIDs, current.ID)
+	}
+
+	keys := make([]string, 0, len(existing))
+	for key := range existing {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		report.warn("'%s' 已从来源中删除，对应任务 '%s' 未自动删除", key, existing[key].Name)
+	}
+
+	err := a.saveTasksToDisk()
+	a.cacheMutex.Unlock()
+
+	return err
+}
+
+// parseOpenAPIDocument 解析JSON或YAML格式的文档
+func parseOpenAPIDocument(data []byte) (map[string]interface{}, error) {
+	data = bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF"))
+
+	var raw interface{}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &raw); err != nil {
+			return nil, fmt.Errorf("解析JSON文档失败：%v", err)
+		}
+	} else {
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("解析YAML文档失败：%v", err)
+		}
+		raw = normalizeYAMLValue(raw)
+	}
+
+	doc, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("文档格式错误：根节点必须是对象")
+	}
+	return doc, nil
+}
+
+// normalizeYAMLValue 将YAML解析出的非字符串键（如响应码 200）统一转换为字符串键
+func normalizeYAMLValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeYAMLValue(item)
+		}
+		return v
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[fmt.Sprintf("%v", key)] = normalizeYAMLValue(item)
+		}
+		return result
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeYAMLValue(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// openAPIConverter 将OpenAPI文档中的操作转换为任务
+type openAPIConverter struct {
+	app     *App
+	doc     map[string]interface{}
+	version int // 2 或 3
+	base    string
+	report  *ImportReport
+}
+
+// baseURL 获取文档中的服务器地址，没有时使用 {{baseUrl}} 变量
+func (c *openAPIConverter) baseURL() string {
+	if c.version == 3 {
+		servers, _ := c.doc["servers"].([]interface{})
+		if len(servers) == 0 {
+			return "{{baseUrl}}"
+		}
+		server := mapValue(servers[0])
+		serverURL := stringValue(server["url"])
+		for name, variable := range mapValue(server["variables"]) {
+			serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", stringValue(mapValue(variable)["default"]))
+		}
+		if !strings.Contains(serverURL, "://") {
+			serverURL = "{{baseUrl}}" + serverURL
+		}
+		return serverURL
+	}
+
+	host := stringValue(c.doc["host"])
+	basePath := stringValue(c.doc["basePath"])
+	if host == "" {
+		return "{{baseUrl}}" + basePath
+	}
+	scheme := "https"
+	if schemes, _ := c.doc["schemes"].([]interface{}); len(schemes) > 0 {
+		scheme = stringValue(schemes[0])
+	}
+	return scheme + "://" + host + basePath
+}
+
+// convert 转换文档中的全部操作
+func (c *openAPIConverter) convert(sourcePath string) []*Task {
+	paths := mapValue(c.doc["paths"])
+	pathKeys := make([]string, 0, len(paths))
+	for key := range paths {
+		pathKeys = append(pathKeys, key)
+	}
+	sort.Strings(pathKeys)
+
+	var tasks []*Task
+	for _, pathKey := range pathKeys {
+		pathItem := c.resolve(paths[pathKey])
+		for _, method := range openAPIMethods {
+			operation, exists := pathItem[method]
+			if !exists {
+				continue
+			}
+			task := c.convertOperation(pathKey, method, pathItem, c.resolve(operation))
+			task.Source = &TaskSource{
+				Type:     "openapi",
+				Path:     sourcePath,
+				Key:      strings.ToUpper(method) + " " + pathKey,
+				Checksum: importedTaskChecksum(task),
+			}
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// convertOperation 将单个操作转换为任务
+func (c *openAPIConverter) convertOperation(pathKey, method string, pathItem, operation map[string]interface{}) *Task {
+	method = strings.ToUpper(method)
+	name := stringValue(operation["summary"])
+	if name == "" {
+		name = stringValue(operation["operationId"])
+	}
+	if name == "" {
+		name = method + " " + pathKey
+	}
+
+	requestPath := pathKey
+	var query []string
+	var headers [][2]string
+	var cookies []string
+	var formFields [][2]string
+	var body string
+	contentType := ""
+
+	for _, param := range c.operationParameters(pathItem, operation) {
+		paramName := stringValue(param["name"])
+		required, _ := param["required"].(bool)
+		value, hasExample := c.parameterExample(param)
+
+		switch stringValue(param["in"]) {
+		case "path":
+			requestPath = strings.ReplaceAll(requestPath, "{"+paramName+"}", value)
+		case "query":
+			if required || hasExample {
+				query = append(query, escapeKeepingPlaceholders(paramName)+"="+escapeKeepingPlaceholders(value))
+			}
+		case "header":
+			if required || hasExample {
+				headers = append(headers, [2]string{paramName, value})
+			}
+		case "cookie":
+			if required || hasExample {
+				cookies = append(cookies, paramName+"="+value)
+			}
+		case "formData":
+			if stringValue(param["type"]) == "file" {
+				c.report.warn("操作 '%s %s' 的文件上传参数 '%s' 不支持，未转换", method, pathKey, paramName)
+				continue
+			}
+			formFields = append(formFields, [2]string{paramName, value})
+		case "body":
+			contentType = c.swaggerContentType(operation, "application/json")
+			body = c.encodeExample(c.exampleFromSchema(c.resolve(param["schema"]), 0), contentType, method, pathKey)
+		}
+	}
+
+	if len(formFields) > 0 {
+		contentType = c.swaggerContentType(operation, "application/x-www-form-urlencoded")
+		body = encodeFormFields(formFields, contentType)
+	}
+
+	if requestBody := c.resolve(operation["requestBody"]); len(requestBody) > 0 {
+		mediaType, media := selectMediaType(mapValue(requestBody["content"]))
+		if mediaType != "" {
+			contentType = mediaType
+			example, found := c.mediaExample(media)
+			if !found {
+				example = c.exampleFromSchema(c.resolve(media["schema"]), 0)
+			}
+			body = c.encodeExample(example, mediaType, method, pathKey)
+		}
+	}
+
+	if contentType != "" {
+		if strings.HasPrefix(contentType, "multipart/form-data") {
+			contentType = multipartContentType
+		}
+		headers = append(headers, [2]string{"Content-Type", contentType})
+	}
+	if len(cookies) > 0 {
+		headers = append(headers, [2]string{"Cookie", strings.Join(cookies, "; ")})
+	}
+
+	// 认证信息使用与安全方案同名的变量占位
+	for _, scheme := range c.securitySchemes(operation) {
+		schemeName := stringValue(scheme["_name"])
+		switch strings.ToLower(stringValue(scheme["type"])) {
+		case "apikey":
+			keyName := stringValue(scheme["name"])
+			switch stringValue(scheme["in"]) {
+			case "header":
+				headers = append(headers, [2]string{keyName, "{{" + schemeName + "}}"})
+			case "query":
+				query = append(query, escapeKeepingPlaceholders(keyName)+"={{"+schemeName+"}}")
+			case "cookie":
+				headers = append(headers, [2]string{"Cookie", keyName + "={{" + schemeName + "}}"})
+			}
+		case "http":
+			switch strings.ToLower(stringValue(scheme["scheme"])) {
+			case "bearer":
+				headers = append(headers, [2]string{"Authorization", "Bearer {{" + schemeName + "}}"})
+			case "basic":
+				headers = append(headers, [2]string{"Authorization", "Basic {{" + schemeName + "}}"})
+			default:
+				c.report.warn("操作 '%s %s' 的认证方式 '%s' 不支持，未转换", method, pathKey, stringValue(scheme["scheme"]))
+			}
+		case "basic":
+			headers = append(headers, [2]string{"Authorization", "Basic {{" + schemeName + "}}"})
+		case "oauth2", "openidconnect":
+			headers = append(headers, [2]string{"Authorization", "Bearer {{" + schemeName + "}}"})
+		}
+	}
+
+	requestURL := c.base + requestPath
+	if len(query) > 0 {
+		requestURL += "?" + strings.Join(query, "&")
+	}
+
+	tags := []string{}
+	if operationTags, ok := operation["tags"].([]interface{}); ok {
+		for _, tag := range operationTags {
+			tags = append(tags, stringValue(tag))
+		}
+	}
+
+	headersText := headersToText(headers)
+	now := time.Now().Unix()
+	return &Task{
+		ID:               newTaskID(),
+		Name:             name,
+		URL:              requestURL,
+		Method:           method,
+		Headers:          c.app.parseHeadersText(headersText),
+		HeadersText:      headersText,
+		Data:             body,
+		Times:            1,
+		Threads:          1,
+		Tags:             tags,
+		SuccessCondition: SuccessCondition{StatusCodes: documentedSuccessCodes(mapValue(operation["responses"]))},
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+}
+
+// operationParameters 合并路径级和操作级参数（操作级同名参数优先）
+func (c *openAPIConverter) operationParameters(pathItem, operation map[string]interface{}) []map[string]interface{} {
+	var result []map[string]interface{}
+	index := make(map[string]int)
+
+	for _, source := range []interface{}{pathItem["parameters"], operation["parameters"]} {
+		params, _ := source.([]interface{})
+		for _, item := range params {
+			param := c.resolve(item)
+			key := stringValue(param["in"]) + ":" + stringValue(param["name"])
+			if i, exists := index[key]; exists {
+				result[i] = param
+				continue
+			}
+			index[key] = len(result)
+			result = append(result, param)
+		}
+	}
+	return result
+}
+
+// parameterExample 获取参数的示例值，没有示例时使用 {{参数名}} 占位
+func (c *openAPIConverter) parameterExample(param map[string]interface{}) (string, bool) {
+	candidates := []interface{}{param["example"], param["default"]}
+	schema := c.resolve(param["schema"])
+	candidates = append(candidates, schema["example"], schema["default"])
+
+	for _, candidate := range candidates {
+		if candidate != nil {
+			return scalarString(candidate), true
+		}
+	}
+	for _, example := range sortedMapValues(mapValue(param["examples"])) {
+		if value := c.resolve(example)["value"]; value != nil {
+			return scalarString(value), true
+		}
+	}
+	return "{{" + stringValue(param["name"]) + "}}", false
+}
+
+// securitySchemes 获取操作使用的安全方案（只取第一组安全要求）
+func (c *openAPIConverter) securitySchemes(operation map[string]interface{}) []map[string]interface{} {
+	requirements, exists := operation["security"].([]interface{})
+	if !exists {
+		requirements, _ = c.doc["security"].([]interface{})
+	}
+	if len(requirements) == 0 {
+		return nil
+	}
+
+	definitions := mapValue(c.doc["securityDefinitions"])
+	if c.version == 3 {
+		definitions = mapValue(mapValue(c.doc["components"])["securitySchemes"])
+	}
+
+	var result []map[string]interface{}
+	for _, name := range sortedMapKeys(mapValue(requirements[0])) {
+		scheme := c.resolve(definitions[name])
+		if len(scheme) == 0 {
+			continue
+		}
+		withName := map[string]interface{}{"_name": name}
+		for key, value := range scheme {
+			withName[key] = value
+		}
+		result = append(result, withName)
+	}
+	return result
+}
+
+// swaggerContentType 获取Swagger 2操作的请求Content-Type
+func (c *openAPIConverter) swaggerContentType(operation map[string]interface{}, fallback string) string {
+	consumes, _ := operation["consumes"].([]interface{})
+	if len(consumes) == 0 {
+		consumes, _ = c.doc["consumes"].([]interface{})
+	}
+	for _, item := range consumes {
+		mediaType := stringValue(item)
+		if fallback == "application/json" && strings.Contains(mediaType, "json") {
+			return mediaType
+		}
+		if fallback != "application/json" && (strings.HasPrefix(mediaType, "application/x-www-form-urlencoded") || strings.HasPrefix(mediaType, "multipart/form-data")) {
+			return mediaType
+		}
+	}
+	return fallback
+}
+
+// encodeExample 按Content-Type将示例值编码为请求体
+func (c *openAPIConverter) encodeExample(example interface{}, contentType, method, pathKey string) string {
+	if example == nil {
+		return ""
+	}
+	if text, ok := example.(string); ok && !strings.Contains(contentType, "json") {
+		return text
+	}
+
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") || strings.HasPrefix(contentType, "multipart/form-data") {
+		object, ok := example.(map[string]interface{})
+		if !ok {
+			c.report.warn("操作 '%s %s' 的表单示例不是对象，未生成请求体", method, pathKey)
+			return ""
+		}
+		var fields [][2]string
+		for _, key := range sortedMapKeys(object) {
+			fields = append(fields, [2]string{key, scalarString(object[key])})
+		}
+		return encodeFormFields(fields, contentType)
+	}
+
+	data, err := json.MarshalIndent(example, "", "  ")
+	if err != nil {
+		c.report.warn("操作 '%s %s' 的示例请求体无法编码：%v", method, pathKey, err)
+		return ""
+	}
+	return string(data)
+}
+
+// exampleFromSchema 根据Schema生成示例值
+func (c *openAPIConverter) exampleFromSchema(schema map[string]interface{}, depth int) interface{} {
+	if len(schema) == 0 || depth > openAPIMaxSchemaDepth {
+		return nil
+	}
+	if example, exists := schema["example"]; exists {
+		return example
+	}
+	if value, exists := schema["default"]; exists {
+		return value
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		merged := make(map[string]interface{})
+		for _, item := range allOf {
+			if object, ok := c.exampleFromSchema(c.resolve(item), depth+1).(map[string]interface{}); ok {
+				for key, value := range object {
+					merged[key] = value
+				}
+			}
+		}
+		return merged
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if options, ok := schema[key].([]interface{}); ok && len(options) > 0 {
+			return c.exampleFromSchema(c.resolve(options[0]), depth+1)
+		}
+	}
+
+	schemaType := stringValue(schema["type"])
+	if types, ok := schema["type"].([]interface{}); ok && len(types) > 0 {
+		schemaType = stringValue(types[0])
+	}
+	if schemaType == "" && schema["properties"] != nil {
+		schemaType = "object"
+	}
+
+	switch schemaType {
+	case "object":
+		result := make(map[string]interface{})
+		for name, property := range mapValue(schema["properties"]) {
+			propertySchema := c.resolve(property)
+			if readOnly, _ := propertySchema["readOnly"].(bool); readOnly {
+				continue
+			}
+			result[name] = c.exampleFromSchema(propertySchema, depth+1)
+		}
+		return result
+	case "array":
+		item := c.exampleFromSchema(c.resolve(schema["items"]), depth+1)
+		if item == nil {
+			return []interface{}{}
+		}
+		return []interface{}{item}
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	case "string":
+		switch stringValue(schema["format"]) {
+		case "date":
+			return "2025-01-01"
+		case "date-time":
+			return "2025-01-01T00:00:00Z"
+		case "email":
+			return "user@example.com"
+		case "uuid":
+			return "00000000-0000-0000-0000-000000000000"
+		case "uri", "url":
+			return "https://example.com"
+		default:
+			return "string"
+		}
+	default:
+		return nil
+	}
+}
+
+// resolve 解析 $ref 引用（只支持文档内引用），返回对象节点
+func (c *openAPIConverter) resolve(node interface{}) map[string]interface{} {
+	current := mapValue(node)
+	for i := 0; i < openAPIMaxSchemaDepth; i++ {
+		ref, ok := current["$ref"].(string)
+		if !ok {
+			return current
+		}
+		if !strings.HasPrefix(ref, "#/") {
+			c.report.warn("外部引用 '%s' 不支持，已忽略", ref)
+			return nil
+		}
+
+		var target interface{} = c.doc
+		for _, part := range strings.Split(ref[2:], "/") {
+			part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+			if unescaped, err := url.PathUnescape(part); err == nil {
+				part = unescaped
+			}
+			target = mapValue(target)[part]
+		}
+		current = mapValue(target)
+	}
+	return current
+}
+
+// selectMediaType 选择请求体的媒体类型（优先JSON，其次表单和文本）
+func selectMediaType(content map[string]interface{}) (string, map[string]interface{}) {
+	keys := sortedMapKeys(content)
+	preferences := []func(string) bool{
+		func(t string) bool { return t == "application/json" },
+		func(t string) bool { return strings.Contains(t, "json") },
+		func(t string) bool { return strings.HasPrefix(t, "application/x-www-form-urlencoded") },
+		func(t string) bool { return strings.HasPrefix(t, "multipart/form-data") },
+		func(t string) bool { return strings.HasPrefix(t, "text/") || strings.Contains(t, "xml") },
+	}
+	for _, matches := range preferences {
+		for _, key := range keys {
+			if matches(key) {
+				return key, mapValue(content[key])
+			}
+		}
+	}
+	if len(keys) > 0 {
+		return keys[0], mapValue(content[keys[0]])
+	}
+	return "", nil
+}
+
+// mediaExample 获取媒体类型中定义的示例
+func (c *openAPIConverter) mediaExample(media map[string]interface{}) (interface{}, bool) {
+	if example, exists := media["example"]; exists {
+		return example, true
+	}
+	for _, example := range sortedMapValues(mapValue(media["examples"])) {
+		if value, exists := c.resolve(example)["value"]; exists {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// documentedSuccessCodes 获取文档中列出的2xx状态码，只有范围（如2XX）或未列出时返回nil（接受任意2xx）
+func documentedSuccessCodes(responses map[string]interface{}) []int {
+	var codes []int
+	for key := range responses {
+		code, err := strconv.Atoi(key)
+		if err != nil {
+			if strings.EqualFold(key, "2XX") {
+				return nil
+			}
+			continue
+		}
+		if code >= 200 && code < 300 {
+			codes = append(codes, code)
+		}
+	}
+	sort.Ints(codes)
+	return codes
+}
+
+// encodeFormFields 按Content-Type编码表单字段
+func encodeFormFields(fields [][2]string, contentType string) string {
+	if strings.HasPrefix(contentType, "multipart/form-data") {
+		return buildMultipartBody(fields)
+	}
+	pairs := make([]string, 0, len(fields))
+	for _, field := range fields {
+		pairs = append(pairs, escapeKeepingPlaceholders(field[0])+"="+escapeKeepingPlaceholders(field[1]))
+	}
+	return strings.Join(pairs, "&")
+}
+
+// importedTaskChecksum 计算导入任务中由来源生成的字段的校验和
+func importedTaskChecksum(task *Task) string {
+	data, _ := json.Marshal([]interface{}{
+		task.Name, task.URL, task.Method, task.HeadersText, task.Data, task.Tags, task.SuccessCondition.StatusCodes,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// mapValue 将节点转换为对象，非对象返回nil
+func mapValue(node interface{}) map[string]interface{} {
+	result, _ := node.(map[string]interface{})
+	return result
+}
+
+// stringValue 将节点转换为字符串，非字符串返回空
+func stringValue(node interface{}) string {
+	result, _ := node.(string)
+	return result
+}
+
+// scalarString 将示例值转换为文本（对象和数组编码为JSON）
+func scalarString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// sortedMapKeys 返回按字母排序的键
+func sortedMapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedMapValues 返回按键排序的值
+func sortedMapValues(m map[string]interface{}) []interface{} {
+	values := make([]interface{}, 0, len(m))
+	for _, key := range sortedMapKeys(m) {
+		values = append(values, m[key])
+	}
+	return values
+}
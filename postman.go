@@ -0,0 +1,532 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Postman Collection v2.1 格式定义（只包含导入需要的字段）
+
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Variable []postmanKeyValue `json:"variable"`
+	Auth     *postmanAuth      `json:"auth"`
+	Event    []postmanEvent    `json:"event"`
+}
+
+type postmanInfo struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+type postmanItem struct {
+	Name    string          `json:"name"`
+	Item    []postmanItem   `json:"item"`    // 文件夹
+	Request *postmanRequest `json:"request"` // 请求
+	Auth    *postmanAuth    `json:"auth"`    // 文件夹级认证
+	Event   []postmanEvent  `json:"event"`
+}
+
+type postmanRequest struct {
+	Method string            `json:"method"`
+	Header []postmanKeyValue `json:"header"`
+	URL    postmanURL        `json:"url"`
+	Body   *postmanBody      `json:"body"`
+	Auth   *postmanAuth      `json:"auth"`
+	Proxy  json.RawMessage   `json:"proxy"`
+	Cert   json.RawMessage   `json:"certificate"`
+}
+
+type postmanKeyValue struct {
+	Key      string      `json:"key"`
+	Value    interface{} `json:"value"`
+	Type     string      `json:"type"`
+	Disabled bool        `json:"disabled"`
+	Enabled  *bool       `json:"enabled"` // 环境文件使用enabled字段
+	Src      interface{} `json:"src"`
+}
+
+type postmanBody struct {
+	Mode       string            `json:"mode"`
+	Raw        string            `json:"raw"`
+	URLEncoded []postmanKeyValue `json:"urlencoded"`
+	FormData   []postmanKeyValue `json:"formdata"`
+	GraphQL    *struct {
+		Query     string `json:"query"`
+		Variables string `json:"variables"`
+	} `json:"graphql"`
+	Options struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	} `json:"options"`
+	Disabled bool `json:"disabled"`
+}
+
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	Basic  []postmanKeyValue `json:"basic"`
+	Bearer []postmanKeyValue `json:"bearer"`
+	APIKey []postmanKeyValue `json:"apikey"`
+}
+
+type postmanEvent struct {
+	Listen string `json:"listen"`
+	Script struct {
+		Exec interface{} `json:"exec"`
+	} `json:"script"`
+	Disabled bool `json:"disabled"`
+}
+
+// postmanURL 支持字符串和对象两种形式
+type postmanURL struct {
+	Raw      string            `json:"raw"`
+	Protocol string            `json:"protocol"`
+	Host     interface{}       `json:"host"`
+	Path     interface{}       `json:"path"`
+	Query    []postmanKeyValue `json:"query"`
+	Variable []postmanKeyValue `json:"variable"`
+}
+
+func (u *postmanURL) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		u.Raw = raw
+		return nil
+	}
+
+	type plain postmanURL
+	var parsed plain
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return err
+	}
+	*u = postmanURL(parsed)
+	return nil
+}
+
+type postmanEnvironment struct {
+	Name   string            `json:"name"`
+	Values []postmanKeyValue `json:"values"`
+	Scope  string            `json:"_postman_variable_scope"`
+}
+
+// Postman路径变量（如 /users/:id）
+var postmanPathVariablePattern = regexp.MustCompile(`/:([A-Za-z_][A-Za-z0-9_]*)`)
+
+// ImportPostmanCollection 导入Postman Collection v2.1文件，path 为空时弹出文件选择对话框
+// 文件夹转换为标签，集合变量只在同名环境变量不存在时导入
+func (a *App) ImportPostmanCollection(path string) ImportReport {
+	path, data, err := a.readImportFile(path, "选择Postman Collection文件", "Postman Collection (*.json)", "*.json")
+	report := newImportReport(path)
+	if err != nil {
+		return report.fail("%v", err)
+	}
+
+	var collection postmanCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return report.fail("解析Postman Collection失败：%v", err)
+	}
+	if collection.Info.Schema != "" && !strings.Contains(collection.Info.Schema, "v2.") {
+		return report.fail("不支持的Collection版本：%s（仅支持v2.0/v2.1）", collection.Info.Schema)
+	}
+
+	a.reportPostmanEvents(&report, "集合", collection.Info.Name, collection.Event)
+
+	var tasks []*Task
+	a.convertPostmanItems(&report, collection.Item, nil, collection.Auth, &tasks)
+
+	if err := a.addTasks(tasks); err != nil {
+		return report.fail("保存任务失败：%v", err)
+	}
+	for _, task := range tasks {
+		report.TaskIDs = append(report.TaskIDs, task.ID)
+	}
+	report.ImportedTasks = len(tasks)
+
+	// 集合变量：不覆盖已有的环境变量
+	existing, err := a.dbGetAllEnvVariables()
+	if err != nil {
+		report.warn("读取环境变量失败，集合变量未导入：%v", err)
+	} else {
+		for _, variable := range collection.Variable {
+			if variable.Key == "" || variable.Disabled {
+				continue
+			}
+			if _, exists := existing[variable.Key]; exists {
+				report.warn("集合变量 '%s' 与已有环境变量同名，未覆盖", variable.Key)
+				continue
+			}
+			if err := a.dbSetEnvVariable(variable.Key, EnvVariableData{Value: postmanValueString(variable.Value)}); err != nil {
+				report.warn("导入集合变量 '%s' 失败：%v", variable.Key, err)
+				continue
+			}
+			report.ImportedVariables++
+		}
+	}
+
+	report.Message = fmt.Sprintf("从 '%s' 导入 %d 个任务、%d 个变量，%d 项未转换",
+		collection.Info.Name, report.ImportedTasks, report.ImportedVariables, len(report.Warnings))
+	return report
+}
+
+// ImportPostmanEnvironment 导入Postman环境文件为环境变量，overwrite 为 false 时跳过已存在的变量
+func (a *App) ImportPostmanEnvironment(path string, overwrite bool) ImportReport {
+	path, data, err := a.readImportFile(path, "选择Postman环境文件", "Postman Environment (*.json)", "*.json")
+	report := newImportReport(path)
+	if err != nil {
+		return report.fail("%v", err)
+	}
+
+	var environment postmanEnvironment
+	if err := json.Unmarshal(data, &environment); err != nil {
+		return report.fail("解析Postman环境文件失败：%v", err)
+	}
+	if environment.Values == nil {
+		return report.fail("文件中没有环境变量（values字段为空）")
+	}
+
+	existing, err := a.dbGetAllEnvVariables()
+	if err != nil {
+		return report.fail("读取环境变量失败：%v", err)
+	}
+
+	for _, variable := range environment.Values {
+		if variable.Key == "" {
+			continue
+		}
+		if variable.Enabled != nil && !*variable.Enabled {
+			report.warn("变量 '%s' 在Postman中已禁用，未导入", variable.Key)
+			continue
+		}
+
+		current, exists := existing[variable.Key]
+		if exists && !overwrite {
+			report.warn("变量 '%s' 已存在，未覆盖", variable.Key)
+			continue
+		}
+
+		// 覆盖时保留原有的分隔符设置
+		data := EnvVariableData{Value: postmanValueString(variable.Value), Separator: current.Separator}
+		if err := a.dbSetEnvVariable(variable.Key, data); err != nil {
+			report.warn("导入变量 '%s' 失败：%v", variable.Key, err)
+			continue
+		}
+		report.ImportedVariables++
+	}
+
+	report.Message = fmt.Sprintf("从环境 '%s' 导入 %d 个变量，%d 项未导入",
+		environment.Name, report.ImportedVariables, len(report.Warnings))
+	return report
+}
+
+// convertPostmanItems 递归转换Postman条目，文件夹名称作为标签
+func (a *App) convertPostmanItems(report *ImportReport, items []postmanItem, folders []string, inheritedAuth *postmanAuth, tasks *[]*Task) {
+	for _, item := range items {
+		if item.Request == nil {
+			// 文件夹
+			auth := inheritedAuth
+			if item.Auth != nil {
+				auth = item.Auth
+			}
+			a.reportPostmanEvents(report, "文件夹", item.Name, item.Event)
+
+			path := append(append([]string{}, folders...), item.Name)
+			a.convertPostmanItems(report, item.Item, path, auth, tasks)
+			continue
+		}
+
+		a.reportPostmanEvents(report, "请求", item.Name, item.Event)
+
+		auth := inheritedAuth
+		if item.Request.Auth != nil {
+			auth = item.Request.Auth
+		}
+
+		task := a.convertPostmanRequest(report, item.Name, item.Request, auth)
+		task.Tags = append([]string{}, folders...)
+		*tasks = append(*tasks, task)
+	}
+}
+
+// convertPostmanRequest 将Postman请求转换为任务
+func (a *App) convertPostmanRequest(report *ImportReport, name string, request *postmanRequest, auth *postmanAuth) *Task {
+	method := strings.ToUpper(request.Method)
+	if method == "" {
+		method = "GET"
+	}
+
+	rawURL := postmanBuildURL(request.URL)
+	var headers [][2]string
+	for _, header := range request.Header {
+		if header.Disabled || header.Key == "" {
+			continue
+		}
+		headers = append(headers, [2]string{header.Key, postmanValueString(header.Value)})
+	}
+
+	// 认证
+	if auth != nil {
+		switch auth.Type {
+		case "", "noauth":
+		case "basic":
+			username := postmanAuthValue(auth.Basic, "username")
+			password := postmanAuthValue(auth.Basic, "password")
+			if strings.Contains(username+password, "{{") {
+				report.warn("请求 '%s' 的Basic认证包含变量，已按原文编码，执行时变量不会被替换", name)
+			}
+			headers = append(headers, [2]string{"Authorization", "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))})
+		case "bearer":
+			headers = append(headers, [2]string{"Authorization", "Bearer " + postmanAuthValue(auth.Bearer, "token")})
+		case "apikey":
+			key := postmanAuthValue(auth.APIKey, "key")
+			value := postmanAuthValue(auth.APIKey, "value")
+			if postmanAuthValue(auth.APIKey, "in") == "query" {
+				separator := "?"
+				if strings.Contains(rawURL, "?") {
+					separator = "&"
+				}
+				rawURL += separator + key + "=" + value
+			} else {
+				headers = append(headers, [2]string{key, value})
+			}
+		default:
+			report.warn("请求 '%s' 使用的认证方式 '%s' 不支持，未转换", name, auth.Type)
+		}
+	}
+
+	// 请求体
+	body := ""
+	if request.Body != nil && !request.Body.Disabled {
+		switch request.Body.Mode {
+		case "", "none":
+		case "raw":
+			body = request.Body.Raw
+			if request.Body.Options.Raw.Language == "json" && !hasCurlHeader(headers, "Content-Type") {
+				headers = append(headers, [2]string{"Content-Type", "application/json"})
+			}
+		case "urlencoded":
+			var pairs []string
+			for _, field := range request.Body.URLEncoded {
+				if field.Disabled {
+					continue
+				}
+				pairs = append(pairs, escapeKeepingPlaceholders(field.Key)+"="+escapeKeepingPlaceholders(postmanValueString(field.Value)))
+			}
+			body = strings.Join(pairs, "&")
+			if !hasCurlHeader(headers, "Content-Type") {
+				headers = append(headers, [2]string{"Content-Type", "application/x-www-form-urlencoded"})
+			}
+		case "formdata":
+			var fields [][2]string
+			for _, field := range request.Body.FormData {
+				if field.Disabled {
+					continue
+				}
+				if field.Type == "file" {
+					report.warn("请求 '%s' 的文件上传字段 '%s' 不支持，未转换", name, field.Key)
+					continue
+				}
+				fields = append(fields, [2]string{field.Key, postmanValueString(field.Value)})
+			}
+			body = buildMultipartBody(fields)
+			headers = removeHeader(headers, "Content-Type")
+			headers = append(headers, [2]string{"Content-Type", multipartContentType})
+		case "graphql":
+			if request.Body.GraphQL != nil {
+				payload := map[string]interface{}{"query": request.Body.GraphQL.Query}
+				if strings.TrimSpace(request.Body.GraphQL.Variables) != "" {
+					payload["variables"] = json.RawMessage(request.Body.GraphQL.Variables)
+				}
+				if data, err := json.Marshal(payload); err == nil {
+					body = string(data)
+				} else {
+					report.warn("请求 '%s' 的GraphQL变量不是有效的JSON，未转换：%v", name, err)
+				}
+				if !hasCurlHeader(headers, "Content-Type") {
+					headers = append(headers, [2]string{"Content-Type", "application/json"})
+				}
+			}
+		default:
+			report.warn("请求 '%s' 的请求体类型 '%s' 不支持，未转换", name, request.Body.Mode)
+		}
+	}
+
+	if len(request.Proxy) > 0 && string(request.Proxy) != "null" {
+		report.warn("请求 '%s' 的代理设置未转换", name)
+	}
+	if len(request.Cert) > 0 && string(request.Cert) != "null" {
+		report.warn("请求 '%s' 的客户端证书设置未转换", name)
+	}
+
+	headersText := headersToText(headers)
+	now := time.Now().Unix()
+
+	if name == "" {
+		name = defaultTaskNameFromURL(method, rawURL)
+	}
+
+	return &Task{
+		ID:          newTaskID(),
+		Name:        name,
+		URL:         rawURL,
+		Method:      method,
+		Headers:     a.parseHeadersText(headersText),
+		HeadersText: headersText,
+		Data:        body,
+		Times:       1,
+		Threads:     1,
+		Tags:        []string{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// reportPostmanEvents 记录无法转换的前置脚本和测试脚本
+func (a *App) reportPostmanEvents(report *ImportReport, kind, name string, events []postmanEvent) {
+	for _, event := range events {
+		if event.Disabled || strings.TrimSpace(postmanScriptText(event.Script.Exec)) == "" {
+			continue
+		}
+		switch event.Listen {
+		case "prerequest":
+			report.warn("%s '%s' 的前置脚本(pre-request script)未转换", kind, name)
+		case "test":
+			report.warn("%s '%s' 的测试脚本(tests)未转换，可使用成功条件代替", kind, name)
+		default:
+			report.warn("%s '%s' 的 %s 脚本未转换", kind, name, event.Listen)
+		}
+	}
+}
+
+// postmanBuildURL 根据Postman URL对象生成URL，路径变量转换为 {{变量}} 占位符
+func postmanBuildURL(u postmanURL) string {
+	rawURL := u.Raw
+	if rawURL == "" {
+		host := strings.Join(postmanStringList(u.Host), ".")
+		path := strings.Join(postmanStringList(u.Path), "/")
+		if u.Protocol != "" {
+			rawURL = u.Protocol + "://"
+		}
+		rawURL += host
+		if path != "" {
+			rawURL += "/" + path
+		}
+
+		var query []string
+		for _, param := range u.Query {
+			if param.Disabled {
+				continue
+			}
+			query = append(query, param.Key+"="+postmanValueString(param.Value))
+		}
+		if len(query) > 0 {
+			rawURL += "?" + strings.Join(query, "&")
+		}
+	} else if len(u.Query) > 0 {
+		// raw中包含被禁用的查询参数时将其移除
+		for _, param := range u.Query {
+			if !param.Disabled {
+				continue
+			}
+			pair := param.Key + "=" + postmanValueString(param.Value)
+			rawURL = strings.Replace(rawURL, "&"+pair, "", 1)
+			rawURL = strings.Replace(rawURL, pair+"&", "", 1)
+			rawURL = strings.Replace(rawURL, "?"+pair, "", 1)
+		}
+	}
+
+	values := make(map[string]string)
+	for _, variable := range u.Variable {
+		values[variable.Key] = postmanValueString(variable.Value)
+	}
+
+	return postmanPathVariablePattern.ReplaceAllStringFunc(rawURL, func(match string) string {
+		name := match[2:]
+		if value, exists := values[name]; exists && value != "" {
+			return "/" + value
+		}
+		return "/{{" + name + "}}"
+	})
+}
+
+// postmanAuthValue 从认证参数列表中获取值
+func postmanAuthValue(params []postmanKeyValue, key string) string {
+	for _, param := range params {
+		if param.Key == key {
+			return postmanValueString(param.Value)
+		}
+	}
+	return ""
+}
+
+// postmanValueString 将Postman中的值转换为字符串
+func postmanValueString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// postmanStringList 将字符串或字符串数组转换为列表
+func postmanStringList(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			result = append(result, postmanValueString(item))
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+// postmanScriptText 将脚本内容（字符串或行数组）合并为文本
+func postmanScriptText(exec interface{}) string {
+	return strings.Join(postmanStringList(exec), "\n")
+}
+
+// escapeKeepingPlaceholders URL编码文本，但保留 {{变量}} 占位符
+func escapeKeepingPlaceholders(text string) string {
+	var builder strings.Builder
+	for {
+		start := strings.Index(text, "{{")
+		if start < 0 {
+			builder.WriteString(url.QueryEscape(text))
+			break
+		}
+		end := strings.Index(text[start:], "}}")
+		if end < 0 {
+			builder.WriteString(url.QueryEscape(text))
+			break
+		}
+		end += start + 2
+		builder.WriteString(url.QueryEscape(text[:start]))
+		builder.WriteString(text[start:end])
+		text = text[end:]
+	}
+	return builder.String()
+}
+
+// removeHeader 移除指定名称的header（不区分大小写）
+func removeHeader(headers [][2]string, name string) [][2]string {
+	result := headers[:0]
+	for _, header := range headers {
+		if !strings.EqualFold(header[0], name) {
+			result = append(result, header)
+		}
+	}
+	return result
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// JUnit XML 格式定义（兼容Jenkins/GitLab等CI的解析器）
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	TestCases  []junitTestCase `xml:"testcase"`
+	SystemOut  string          `xml:"system-out,omitempty"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// ExportExecutionLogJUnit 将执行日志导出为JUnit XML（每个请求一个testcase）
+// filterJson 为 LogExportFilter 的JSON（为空时导出全部），path 为空时弹出保存对话框
+func (a *App) ExportExecutionLogJUnit(taskLogID, filterJson, path string) string {
+	executionLog, err := a.getExportExecutionLog(taskLogID, filterJson)
+	if err != nil {
+		return fmt.Sprintf("错误：%v", err)
+	}
+
+	data, err := buildJUnitReport(executionLog, a.taskNameForLog(taskLogID))
+	if err != nil {
+		return fmt.Sprintf("导出失败：%v", err)
+	}
+
+	path, err = a.resolveExportPath(path, exportFileName(taskLogID, "xml"), "JUnit XML (*.xml)", "*.xml")
+	if err != nil {
+		return fmt.Sprintf("错误：%v", err)
+	}
+
+	if err := writeExportFile(path, data); err != nil {
+		return fmt.Sprintf("导出失败：%v", err)
+	}
+
+	return fmt.Sprintf("已导出 %d 条请求到 %s", len(executionLog.DetailedLogs), path)
+}
+
+// ExportExecutionLogCSV 将执行日志导出为CSV（每条详细日志一行）
+// filterJson 为 LogExportFilter 的JSON（为空时导出全部），path 为空时弹出保存对话框
+func (a *App) ExportExecutionLogCSV(taskLogID, filterJson, path string) string {
+	executionLog, err := a.getExportExecutionLog(taskLogID, filterJson)
+	if err != nil {
+		return fmt.Sprintf("错误：%v", err)
+	}
+
+	data, err := buildCSVReport(executionLog)
+	if err != nil {
+		return fmt.Sprintf("导出失败：%v", err)
+	}
+
+	path, err = a.resolveExportPath(path, exportFileName(taskLogID, "csv"), "CSV文件 (*.csv)", "*.csv")
+	if err != nil {
+		return fmt.Sprintf("错误：%v", err)
+	}
+
+	if err := writeExportFile(path, data); err != nil {
+		return fmt.Sprintf("导出失败：%v", err)
+	}
+
+	return fmt.Sprintf("已导出 %d 条请求到 %s", len(executionLog.DetailedLogs), path)
+}
+
+// buildJUnitReport 构建JUnit XML报告
+func buildJUnitReport(executionLog *ExecutionLog, suiteName string) ([]byte, error) {
+	if suiteName == "" {
+		suiteName = executionLog.TaskLogID
+	}
+
+	suite := junitTestSuite{
+		Name:  suiteName,
+		Tests: len(executionLog.DetailedLogs),
+		Time:  strconv.FormatInt(executionLog.Duration, 10),
+		Properties: []junitProperty{
+			{Name: "taskLogId", Value: executionLog.TaskLogID},
+			{Name: "summary", Value: executionLog.Summary},
+			{Name: "totalRequests", Value: strconv.Itoa(executionLog.TotalRequests)},
+		},
+		TestCases: make([]junitTestCase, 0, len(executionLog.DetailedLogs)),
+		SystemOut: executionLog.Summary,
+	}
+
+	for i, detail := range executionLog.DetailedLogs {
+		if i == 0 {
+			if started, err := time.ParseInLocation("2006-01-02 15:04:05", detail.Timestamp, time.Local); err == nil {
+				suite.Timestamp = started.Format("2006-01-02T15:04:05")
+			}
+		}
+
+		testCase := junitTestCase{
+			Name:      fmt.Sprintf("#%d %s %s", i+1, detail.Method, detail.URL),
+			ClassName: suiteName,
+			Time:      fmt.Sprintf("%.3f", float64(detail.ResponseTime)/1000),
+		}
+
+		if !detail.Success {
+			message := detail.Error
+			if message == "" {
+				message = "请求失败"
+			}
+			body := detail.DetailedError
+			if body == "" {
+				body = message
+			}
+			problem := &junitProblem{Message: message, Type: detail.ErrorType, Body: body}
+
+			// 网络和解析错误属于执行错误，状态码和成功条件不满足属于断言失败
+			if detail.ErrorType == "network" || detail.ErrorType == "parsing" {
+				testCase.Error = problem
+				suite.Errors++
+			} else {
+				testCase.Failure = problem
+				suite.Failures++
+			}
+			testCase.SystemOut = detail.Response
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	report := junitTestSuites{
+		Name:     suiteName,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+// buildCSVReport 构建CSV报告（带UTF-8 BOM，便于Excel正确显示中文）
+func buildCSVReport(executionLog *ExecutionLog) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\xEF\xBB\xBF")
+
+	writer := csv.NewWriter(&buf)
+	header := []string{
+		"requestId", "timestamp", "method", "url", "statusCode", "responseTimeMs",
+		"success", "errorType", "error", "detailedError", "conditionActualValue", "response",
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	for _, detail := range executionLog.DetailedLogs {
+		actualValue := ""
+		if detail.SuccessConditionDetails != nil {
+			actualValue = detail.SuccessConditionDetails.ActualValue
+		}
+
+		record := []string{
+			detail.RequestID,
+			detail.Timestamp,
+			detail.Method,
+			detail.URL,
+			strconv.Itoa(detail.StatusCode),
+			strconv.FormatInt(detail.ResponseTime, 10),
+			strconv.FormatBool(detail.Success),
+			detail.ErrorType,
+			detail.Error,
+			detail.DetailedError,
+			actualValue,
+			detail.Response,
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// taskNameForLog 根据执行日志ID获取任务名称
+func (a *App) taskNameForLog(taskLogID string) string {
+	a.logMutex.RLock()
+	taskID := a.findTaskIDByLogIDLocked(taskLogID)
+	a.logMutex.RUnlock()
+
+	a.cacheMutex.RLock()
+	defer a.cacheMutex.RUnlock()
+
+	if task, exists := a.tasksCache[taskID]; exists {
+		return task.Name
+	}
+	return taskID
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// RetentionPolicy 日志保留策略
+type RetentionPolicy struct {
+	MaxAgeDays        int  `json:"maxAgeDays"`        // 日志最长保留天数，0 表示不按时间清理
+	MaxRuns           int  `json:"maxRuns"`           // 每个任务最多保留的执行日志条数，0 表示不限制
+	MaxResponseBytes  int  `json:"maxResponseBytes"`  // 每个请求最多保存的响应内容字节数，0 表示不保存响应内容
+	KeepSuccessBodies bool `json:"keepSuccessBodies"` // 是否保存成功请求的响应内容
+}
+
+// CleanupReport 日志清理报告
+type CleanupReport struct {
+	Time                 string `json:"time"`                 // 清理时间
+	Trigger              string `json:"trigger"`              // 触发方式: startup, scheduled, manual
+	RemovedTaskLogs      int    `json:"removedTaskLogs"`      // 删除的任务日志条数
+	RemovedExecutionLogs int    `json:"removedExecutionLogs"` // 删除的详细执行日志数
+	RemovedHistory       int    `json:"removedHistory"`       // 删除的执行历史和定时触发记录条数
+	TruncatedResponses   int    `json:"truncatedResponses"`   // 被截断或清空的响应内容数
+	BytesBefore          int64  `json:"bytesBefore"`          // 清理前日志文件大小
+	BytesAfter           int64  `json:"bytesAfter"`           // 清理后日志文件大小
+	ReclaimedBytes       int64  `json:"reclaimedBytes"`       // 回收的空间
+	Message              string `json:"message"`              // 清理结果描述
+}
+
+// 评估成功条件时最多读取的响应内容（保存到日志的部分由保留策略决定）
+const maxEvaluatedResponseBytes = 1024 * 1024
+
+// defaultRetentionPolicy 默认保留策略（与早期硬编码的行为一致）
+func defaultRetentionPolicy() RetentionPolicy {
+	return RetentionPolicy{
+		MaxAgeDays:        30,
+		MaxRuns:           100,
+		MaxResponseBytes:  1024 * 10,
+		KeepSuccessBodies: true,
+	}
+}
+
+// validate 校验保留策略
+func (p RetentionPolicy) validate() error {
+	if p.MaxAgeDays < 0 {
+		return fmt.Errorf("保留天数不能为负数")
+	}
+	if p.MaxRuns < 0 {
+		return fmt.Errorf("保留条数不能为负数")
+	}
+	if p.MaxResponseBytes < 0 {
+		return fmt.Errorf("响应内容大小限制不能为负数")
+	}
+	return nil
+}
+
+// getRetentionPolicy 获取任务生效的保留策略（任务未单独设置时使用全局策略）
+func (a *App) getRetentionPolicy(taskID string) RetentionPolicy {
+	a.cacheMutex.RLock()
+	task, exists := a.tasksCache[taskID]
+	var policy *RetentionPolicy
+	if exists {
+		policy = task.Retention
+	}
+	a.cacheMutex.RUnlock()
+
+	if policy != nil {
+		return *policy
+	}
+	return a.getSettings().Retention
+}
+
+// SetTaskRetention 设置任务的保留策略，policyJson 为空时恢复使用全局策略
+func (a *App) SetTaskRetention(taskID, policyJson string) string {
+	var policy *RetentionPolicy
+	if policyJson != "" {
+		policy = &RetentionPolicy{}
+		if err := json.Unmarshal([]byte(policyJson), policy); err != nil {
+			return fmt.Sprintf("数据格式错误：%v", err)
+		}
+		if err := policy.validate(); err != nil {
+			return fmt.Sprintf("错误：%v", err)
+		}
+	}
+
+	a.cacheMutex.Lock()
+	defer a.cacheMutex.Unlock()
+
+	task, exists := a.tasksCache[taskID]
+	if !exists {
+		return "错误：任务不存在"
+	}
+
+	task.Retention = policy
+	task.UpdatedAt = time.Now().Unix()
+
+	// 保存到磁盘
+	if err := a.saveTasksToDisk(); err != nil {
+		return fmt.Sprintf("保存失败：%v", err)
+	}
+
+	if policy == nil {
+		return fmt.Sprintf("任务 '%s' 已恢复使用全局保留策略", task.Name)
+	}
+	return fmt.Sprintf("任务 '%s' 的保留策略设置成功", task.Name)
+}
+
+// retainResponseBody 按保留策略处理要保存的响应内容
+func retainResponseBody(body string, success bool, policy RetentionPolicy) string {
+	if success && !policy.KeepSuccessBodies {
+		return ""
+	}
+	return truncateUTF8(body, policy.MaxResponseBytes)
+}
+
+// RunLogCleanup 立即按保留策略清理日志
+func (a *App) RunLogCleanup() CleanupReport {
+	return a.runLogCleanup("manual")
+}
+
+// GetLastCleanupReport 获取最近一次日志清理报告
+func (a *App) GetLastCleanupReport() CleanupReport {
+	a.logMutex.RLock()
+	defer a.logMutex.RUnlock()
+	return a.lastCleanupReport
+}
+
+// runLogCleanup 清理日志并统计回收的空间
+func (a *App) runLogCleanup(trigger string) CleanupReport {
+	report := CleanupReport{
+		Time:    time.Now().Format("2006-01-02 15:04:05"),
+		Trigger: trigger,
+	}
+
+	report.BytesBefore = a.logFilesSize()
+	report.RemovedTaskLogs, report.RemovedExecutionLogs, report.TruncatedResponses = a.cleanupOldLogs()
+	report.RemovedHistory = a.pruneRunHistory() + a.pruneScheduleHistory()
+
+	if report.RemovedTaskLogs > 0 || report.RemovedExecutionLogs > 0 || report.TruncatedResponses > 0 {
+		if err := a.saveTaskLogs(); err != nil {
+			fmt.Printf("保存任务日志失败: %v\n", err)
+		}
+		if err := a.saveExecutionLogs(); err != nil {
+			fmt.Printf("保存执行日志失败: %v\n", err)
+		}
+	}
+
+	report.BytesAfter = a.logFilesSize()
+	report.ReclaimedBytes = report.BytesBefore - report.BytesAfter
+	if report.ReclaimedBytes < 0 {
+		report.ReclaimedBytes = 0
+	}
+	report.Message = fmt.Sprintf("清理完成：删除任务日志%d条、详细日志%d个、历史记录%d条，截断响应%d个，回收空间%.1fKB",
+		report.RemovedTaskLogs, report.RemovedExecutionLogs, report.RemovedHistory, report.TruncatedResponses, float64(report.ReclaimedBytes)/1024)
+
+	a.logMutex.Lock()
+	a.lastCleanupReport = report
+	a.logMutex.Unlock()
+
+	return report
+}
+
+// logCleanupLoop 后台定期清理日志，直到应用关闭
+func (a *App) logCleanupLoop() {
+	for {
+		interval := time.Duration(a.getSettings().CleanupIntervalMinutes) * time.Minute
+		if interval <= 0 {
+			interval = time.Hour
+		}
+
+		select {
+		case <-a.shutdown:
+			return
+		case <-time.After(interval):
+			report := a.runLogCleanup("scheduled")
+			if report.ReclaimedBytes > 0 {
+				fmt.Println(report.Message)
+			}
+		}
+	}
+}
+
+// logFilesSize 统计日志文件占用的空间
+func (a *App) logFilesSize() int64 {
+	var total int64
+	for _, path := range []string{a.getTaskLogsPath(), a.getExecutionLogsPath()} {
+		if info, err := os.Stat(path); err == nil {
+			total += info.Size()
+		}
+	}
+	return total
+}
@@ -0,0 +1,372 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// RunRecord 执行历史记录（持久化在SQLite中，不受任务日志条数限制）
+type RunRecord struct {
+	TaskID        string  `json:"taskId"`
+	TaskLogID     string  `json:"taskLogId"`
+	Status        string  `json:"status"`        // success, partial, failed
+	StartedAt     int64   `json:"startedAt"`     // 开始时间（Unix秒）
+	Duration      int64   `json:"duration"`      // 执行时长(秒)
+	TotalRequests int     `json:"totalRequests"` // 总请求数
+	SuccessCount  int     `json:"successCount"`  // 成功数
+	FailedCount   int     `json:"failedCount"`   // 失败数
+	AvgLatency    float64 `json:"avgLatency"`    // 平均响应时间(毫秒)
+	P95Latency    int64   `json:"p95Latency"`    // 95分位响应时间(毫秒)
+	MaxLatency    int64   `json:"maxLatency"`    // 最大响应时间(毫秒)
+}
+
+// TrendPoint 趋势数据点
+type TrendPoint struct {
+	Time          string  `json:"time"`          // 数据点时间（按执行时为开始时间，按小时/天时为桶开始时间）
+	TaskLogID     string  `json:"taskLogId"`     // 执行日志ID（仅按执行统计时有值）
+	Status        string  `json:"status"`        // 执行状态（仅按执行统计时有值）
+	RunCount      int     `json:"runCount"`      // 执行次数
+	TotalRequests int     `json:"totalRequests"` // 总请求数
+	SuccessCount  int     `json:"successCount"`  // 成功数
+	FailedCount   int     `json:"failedCount"`   // 失败数
+	SuccessRate   float64 `json:"successRate"`   // 成功率(百分比)
+	AvgLatency    float64 `json:"avgLatency"`    // 平均响应时间(毫秒)
+	P95Latency    int64   `json:"p95Latency"`    // 95分位响应时间(毫秒)，按小时/天时为各次执行P95按请求数加权的平均值
+	MaxP95Latency int64   `json:"maxP95Latency"` // 桶内各次执行P95的最大值(毫秒)
+}
+
+// TaskTrend 任务历史趋势
+type TaskTrend struct {
+	TaskID      string       `json:"taskId"`
+	Granularity string       `json:"granularity"` // run, hour, day
+	StartTime   string       `json:"startTime"`
+	EndTime     string       `json:"endTime"`
+	Points      []TrendPoint `json:"points"`
+	Error       string       `json:"error"`
+}
+
+// GetTaskTrend 获取任务在时间窗口内的历史趋势
+// granularity 可选 run（每次执行一个点）、hour、day；startTime/endTime 格式为 "2006-01-02 15:04:05"，
+// endTime 为空表示当前时间，startTime 为空表示 endTime 前7天
+func (a *App) GetTaskTrend(taskID, granularity, startTime, endTime string) TaskTrend {
+	if granularity == "" {
+		granularity = "run"
+	}
+
+	trend := TaskTrend{
+		TaskID:      taskID,
+		Granularity: granularity,
+		Points:      []TrendPoint{},
+	}
+
+	if granularity != "run" && granularity != "hour" && granularity != "day" {
+		trend.Error = fmt.Sprintf("不支持的统计粒度：%s", granularity)
+		return trend
+	}
+
+	end := time.Now()
+	if endTime != "" {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04:05", endTime, time.Local)
+		if err != nil {
+			trend.Error = fmt.Sprintf("结束时间格式错误：%v", err)
+			return trend
+		}
+		end = parsed
+	}
+
+	start := end.AddDate(0, 0, -7)
+	if startTime != "" {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04:05", startTime, time.Local)
+		if err != nil {
+			trend.Error = fmt.Sprintf("开始时间格式错误：%v", err)
+			return trend
+		}
+		start = parsed
+	}
+
+	trend.StartTime = start.Format("2006-01-02 15:04:05")
+	trend.EndTime = end.Format("2006-01-02 15:04:05")
+
+	records, err := a.dbGetRunRecords(taskID, start.Unix(), end.Unix())
+	if err != nil {
+		trend.Error = fmt.Sprintf("查询执行历史失败：%v", err)
+		return trend
+	}
+
+	if granularity == "run" {
+		for _, record := range records {
+			point := TrendPoint{
+				Time:          time.Unix(record.StartedAt, 0).Format("2006-01-02 15:04:05"),
+				TaskLogID:     record.TaskLogID,
+				Status:        record.Status,
+				RunCount:      1,
+				TotalRequests: record.TotalRequests,
+				SuccessCount:  record.SuccessCount,
+				FailedCount:   record.FailedCount,
+				AvgLatency:    record.AvgLatency,
+				P95Latency:    record.P95Latency,
+				MaxP95Latency: record.P95Latency,
+			}
+			if record.TotalRequests > 0 {
+				point.SuccessRate = float64(record.SuccessCount) / float64(record.TotalRequests) * 100
+			}
+			trend.Points = append(trend.Points, point)
+		}
+		return trend
+	}
+
+	trend.Points = bucketRunRecords(records, granularity, start, end)
+	return trend
+}
+
+// bucketRunRecords 将执行记录按小时或天聚合，空桶也会返回以便显示间隙
+func bucketRunRecords(records []RunRecord, granularity string, start, end time.Time) []TrendPoint {
+	truncate := func(t time.Time) time.Time {
+		if granularity == "hour" {
+			return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+		}
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	}
+	next := func(t time.Time) time.Time {
+		if granularity == "hour" {
+			return t.Add(time.Hour)
+		}
+		return t.AddDate(0, 0, 1)
+	}
+
+	var points []TrendPoint
+	index := make(map[int64]int)
+	for bucket := truncate(start); !bucket.After(end); bucket = next(bucket) {
+		index[bucket.Unix()] = len(points)
+		points = append(points, TrendPoint{Time: bucket.Format("2006-01-02 15:04:05")})
+	}
+
+	latencySum := make([]float64, len(points))
+	p95Sum := make([]float64, len(points))
+	for _, record := range records {
+		i, exists := index[truncate(time.Unix(record.StartedAt, 0)).Unix()]
+		if !exists {
+			continue
+		}
+
+		point := &points[i]
+		point.RunCount++
+		point.TotalRequests += record.TotalRequests
+		point.SuccessCount += record.SuccessCount
+		point.FailedCount += record.FailedCount
+		latencySum[i] += record.AvgLatency * float64(record.TotalRequests)
+		p95Sum[i] += float64(record.P95Latency) * float64(record.TotalRequests)
+		if record.P95Latency > point.MaxP95Latency {
+			point.MaxP95Latency = record.P95Latency
+		}
+	}
+
+	for i := range points {
+		if points[i].TotalRequests == 0 {
+			continue
+		}
+		total := float64(points[i].TotalRequests)
+		points[i].SuccessRate = float64(points[i].SuccessCount) / total * 100
+		points[i].AvgLatency = latencySum[i] / total
+		points[i].P95Latency = int64(p95Sum[i] / total)
+	}
+
+	if points == nil {
+		points = []TrendPoint{}
+	}
+	return points
+}
+
+// recordRunHistory 根据执行日志写入执行历史记录
+func (a *App) recordRunHistory(taskID, taskLogID, status string, startedAt, duration int64) {
+	a.logMutex.RLock()
+	executionLog, exists := a.executionLogs[taskLogID]
+	a.logMutex.RUnlock()
+
+	if !exists {
+		return
+	}
+
+	if err := a.dbInsertRunRecord(newRunRecord(taskID, taskLogID, status, startedAt, executionLog), false); err != nil {
+		fmt.Printf("写入执行历史失败: %v\n", err)
+	}
+}
+
+// backfillRunHistory 从已加载的日志中补录缺失的执行历史记录
+func (a *App) backfillRunHistory() {
+	a.logMutex.RLock()
+	var records []RunRecord
+	for taskID, logs := range a.taskLogs {
+		for _, entry := range logs {
+			if entry.ExecutionLogId == "" {
+				continue
+			}
+			executionLog, exists := a.executionLogs[entry.ExecutionLogId]
+			if !exists {
+				continue
+			}
+			finishedAt, err := time.ParseInLocation("2006-01-02 15:04:05", entry.Timestamp, time.Local)
+			if err != nil {
+				continue
+			}
+			startedAt := finishedAt.Unix() - executionLog.Duration
+			records = append(records, newRunRecord(taskID, entry.ExecutionLogId, entry.Status, startedAt, executionLog))
+		}
+	}
+	a.logMutex.RUnlock()
+
+	for _, record := range records {
+		if err := a.dbInsertRunRecord(record, true); err != nil {
+			fmt.Printf("补录执行历史失败: %v\n", err)
+			return
+		}
+	}
+}
+
+// newRunRecord 根据执行日志构建执行历史记录
+func newRunRecord(taskID, taskLogID, status string, startedAt int64, executionLog ExecutionLog) RunRecord {
+	record := RunRecord{
+		TaskID:        taskID,
+		TaskLogID:     taskLogID,
+		Status:        status,
+		StartedAt:     startedAt,
+		Duration:      executionLog.Duration,
+		TotalRequests: executionLog.TotalRequests,
+		SuccessCount:  executionLog.SuccessCount,
+		FailedCount:   executionLog.FailedCount,
+	}
+
+	stats := executionLog.Stats
+	if stats == nil {
+		stats = computeRunStats(executionLog.DetailedLogs)
+	}
+	record.AvgLatency = stats.Mean
+	record.P95Latency = stats.P95
+	record.MaxLatency = stats.Max
+
+	return record
+}
+
+// dbInsertRunRecord 写入执行历史记录，ignoreExisting 为 true 时跳过已存在的记录
+func (a *App) dbInsertRunRecord(record RunRecord, ignoreExisting bool) error {
+	a.dbMutex.Lock()
+	defer a.dbMutex.Unlock()
+
+	verb := "INSERT OR REPLACE"
+	if ignoreExisting {
+		verb = "INSERT OR IGNORE"
+	}
+
+	query := verb + ` INTO run_history
+	(task_id, task_log_id, status, started_at, duration, total_requests, success_count, failed_count, avg_latency, p95_latency, max_latency)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := a.db.Exec(query, record.TaskID, record.TaskLogID, record.Status, record.StartedAt, record.Duration,
+		record.TotalRequests, record.SuccessCount, record.FailedCount, record.AvgLatency, record.P95Latency, record.MaxLatency)
+	return err
+}
+
+// dbGetRunRecords 查询任务在时间范围内的执行历史记录（按开始时间升序）
+func (a *App) dbGetRunRecords(taskID string, start, end int64) ([]RunRecord, error) {
+	a.dbMutex.RLock()
+	defer a.dbMutex.RUnlock()
+
+	query := `
+	SELECT task_id, task_log_id, status, started_at, duration, total_requests, success_count, failed_count, avg_latency, p95_latency, max_latency
+	FROM run_history
+	WHERE task_id = ? AND started_at >= ? AND started_at <= ?
+	ORDER BY started_at
+	`
+
+	rows, err := a.db.Query(query, taskID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []RunRecord
+	for rows.Next() {
+		var record RunRecord
+		if err := rows.Scan(&record.TaskID, &record.TaskLogID, &record.Status, &record.StartedAt, &record.Duration,
+			&record.TotalRequests, &record.SuccessCount, &record.FailedCount, &record.AvgLatency, &record.P95Latency, &record.MaxLatency); err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+// pruneRunHistory 按各任务保留策略的保留天数清理执行历史记录，返回删除的条数
+// 执行历史用于长期趋势统计，不受执行日志保留条数的限制
+func (a *App) pruneRunHistory() int {
+	taskIDs, err := a.dbGetRunHistoryTaskIDs()
+	if err != nil {
+		fmt.Printf("查询执行历史失败: %v\n", err)
+		return 0
+	}
+
+	removed := 0
+	for _, taskID := range taskIDs {
+		policy := a.getRetentionPolicy(taskID)
+		if policy.MaxAgeDays <= 0 {
+			continue
+		}
+		count, err := a.dbPruneRunRecords(taskID, time.Now().AddDate(0, 0, -policy.MaxAgeDays))
+		if err != nil {
+			fmt.Printf("清理执行历史失败: %v\n", err)
+			continue
+		}
+		removed += count
+	}
+	return removed
+}
+
+// dbGetRunHistoryTaskIDs 查询有执行历史记录的任务ID
+func (a *App) dbGetRunHistoryTaskIDs() ([]string, error) {
+	a.dbMutex.RLock()
+	defer a.dbMutex.RUnlock()
+
+	rows, err := a.db.Query("SELECT DISTINCT task_id FROM run_history")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var taskIDs []string
+	for rows.Next() {
+		var taskID string
+		if err := rows.Scan(&taskID); err != nil {
+			return taskIDs, err
+		}
+		taskIDs = append(taskIDs, taskID)
+	}
+	return taskIDs, rows.Err()
+}
+
+// dbPruneRunRecords 删除任务在 cutoff 之前开始的执行历史记录，返回删除的条数
+func (a *App) dbPruneRunRecords(taskID string, cutoff time.Time) (int, error) {
+	a.dbMutex.Lock()
+	defer a.dbMutex.Unlock()
+
+	result, err := a.db.Exec("DELETE FROM run_history WHERE task_id = ? AND started_at < ?", taskID, cutoff.Unix())
+	if err != nil {
+		return 0, err
+	}
+	count, _ := result.RowsAffected()
+	return int(count), nil
+}
+
+// dbDeleteRunRecords 删除任务的执行历史记录（taskID 为 "all" 时删除所有记录）
+func (a *App) dbDeleteRunRecords(taskID string) error {
+	a.dbMutex.Lock()
+	defer a.dbMutex.Unlock()
+
+	if taskID == "all" {
+		_, err := a.db.Exec("DELETE FROM run_history")
+		return err
+	}
+	_, err := a.db.Exec("DELETE FROM run_history WHERE task_id = ?", taskID)
+	return err
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// 调度信息中显示的执行时间数量，以及计算时最多检查的触发次数
+const (
+	upcomingRunCount     = 5
+	maxScheduleLookahead = 1000
+)
+
+// 定时触发时上一次执行仍未结束的处理方式
+const (
+	OverlapSkip   = "skip"   // 跳过本次触发
+	OverlapQueue  = "queue"  // 排队一次，上一次结束后执行
+	OverlapAllow  = "allow"  // 允许同时执行
+	OverlapCancel = "cancel" // 停止上一次执行后重新开始
+)
+
+// ScheduleOptions 任务的定时调度选项
+type ScheduleOptions struct {
+	Overlap      string `json:"overlap"`      // skip, queue, allow, cancel（为空时跳过）
+	TimeZone     string `json:"timeZone"`     // IANA时区，如 Asia/Shanghai（为空时使用本机时区）
+	Jitter       int    `json:"jitter"`       // 触发后随机延迟的最大秒数，用于错开多个任务的请求
+	StartDate    string `json:"startDate"`    // 生效开始日期 2006-01-02（含）
+	EndDate      string `json:"endDate"`      // 生效结束日期 2006-01-02（含）
+	DailyStart   string `json:"dailyStart"`   // 每天生效的开始时间 15:04（含）
+	DailyEnd     string `json:"dailyEnd"`     // 每天生效的结束时间 15:04（不含，早于开始时间表示跨午夜）
+	Misfire      string `json:"misfire"`      // 错过执行的处理方式：ignore, once, all（为空时忽略）
+	MisfireLimit int    `json:"misfireLimit"` // all 时最多补偿的次数（为0时为10）
+}
+
+// validate 校验调度选项
+func (o *ScheduleOptions) validate() error {
+	switch o.Overlap {
+	case "", OverlapSkip, OverlapQueue, OverlapAllow, OverlapCancel:
+	default:
+		return fmt.Errorf("不支持的重叠处理方式：%s", o.Overlap)
+	}
+	switch o.Misfire {
+	case "", MisfireIgnore, MisfireOnce, MisfireAll:
+	default:
+		return fmt.Errorf("不支持的错过执行处理方式：%s", o.Misfire)
+	}
+	if o.MisfireLimit < 0 || o.MisfireLimit > maxMisfireLimit {
+		return fmt.Errorf("补偿执行次数必须在0-%d之间", maxMisfireLimit)
+	}
+	if _, err := loadScheduleLocation(o.TimeZone); err != nil {
+		return err
+	}
+	if o.Jitter < 0 || o.Jitter > 86400 {
+		return fmt.Errorf("随机延迟必须在0-86400秒之间")
+	}
+
+	for _, date := range []string{o.StartDate, o.EndDate} {
+		if _, err := time.Parse("2006-01-02", date); date != "" && err != nil {
+			return fmt.Errorf("日期格式错误：%s（应为 2006-01-02）", date)
+		}
+	}
+	if o.StartDate != "" && o.EndDate != "" && o.StartDate > o.EndDate {
+		return fmt.Errorf("生效开始日期不能晚于结束日期")
+	}
+
+	if (o.DailyStart == "") != (o.DailyEnd == "") {
+		return fmt.Errorf("每天生效时间需要同时设置开始和结束时间")
+	}
+	for _, clock := range []string{o.DailyStart, o.DailyEnd} {
+		if _, err := time.Parse("15:04", clock); clock != "" && err != nil {
+			return fmt.Errorf("时间格式错误：%s（应为 15:04）", clock)
+		}
+	}
+	if o.DailyStart != "" && o.DailyStart == o.DailyEnd {
+		return fmt.Errorf("每天生效的开始和结束时间不能相同")
+	}
+	return nil
+}
+
+// loadScheduleLocation 加载调度时区（为空时使用本机时区）
+func loadScheduleLocation(timeZone string) (*time.Location, error) {
+	if timeZone == "" {
+		return time.Local, nil
+	}
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("无效的时区：%s", timeZone)
+	}
+	return location, nil
+}
+
+// nextActiveTime 返回不早于 t 的最近生效时间，超出生效结束日期时返回 false
+func (o ScheduleOptions) nextActiveTime(t time.Time, location *time.Location) (time.Time, bool) {
+	t = t.In(location)
+
+	// 生效开始日期之前跳到开始日期零点
+	if o.StartDate != "" {
+		if start, err := time.ParseInLocation("2006-01-02", o.StartDate, location); err == nil && t.Before(start) {
+			t = start
+		}
+	}
+
+	// 每天生效时间之外跳到下一次开始时间
+	if o.DailyStart != "" && o.DailyEnd != "" {
+		clock := t.Format("15:04")
+		var inWindow bool
+		if o.DailyStart < o.DailyEnd {
+			inWindow = clock >= o.DailyStart && clock < o.DailyEnd
+		} else {
+			inWindow = clock >= o.DailyStart || clock < o.DailyEnd
+		}
+
+		if !inWindow {
+			start, _ := time.ParseInLocation("2006-01-02 15:04", t.Format("2006-01-02")+" "+o.DailyStart, location)
+			if !start.After(t) {
+				start = start.AddDate(0, 0, 1)
+			}
+			t = start
+		}
+	}
+
+	if o.EndDate != "" && t.Format("2006-01-02") > o.EndDate {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// isActive 判断触发时间是否在生效时间范围内
+func (o ScheduleOptions) isActive(t time.Time, location *time.Location) bool {
+	active, ok := o.nextActiveTime(t, location)
+	return ok && active.Equal(t)
+}
+
+// describeWindow 生成生效时间范围和随机延迟的描述
+func (o ScheduleOptions) describeWindow() string {
+	var parts []string
+	if o.DailyStart != "" {
+		parts = append(parts, fmt.Sprintf("每天%s-%s", o.DailyStart, o.DailyEnd))
+	}
+	switch {
+	case o.StartDate != "" && o.EndDate != "":
+		parts = append(parts, fmt.Sprintf("%s至%s", o.StartDate, o.EndDate))
+	case o.StartDate != "":
+		parts = append(parts, o.StartDate+"起")
+	case o.EndDate != "":
+		parts = append(parts, "至"+o.EndDate)
+	}
+	if o.Jitter > 0 {
+		parts = append(parts, fmt.Sprintf("随机延迟0-%d秒", o.Jitter))
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+	return "，" + strings.Join(parts, "，")
+}
+
+// getScheduleOptions 获取任务的调度选项（未设置时使用默认值）
+func (a *App) getScheduleOptions(task *Task) ScheduleOptions {
+	a.cacheMutex.RLock()
+	defer a.cacheMutex.RUnlock()
+
+	options := ScheduleOptions{Overlap: OverlapSkip}
+	if task.Schedule != nil {
+		options = *task.Schedule
+	}
+	if options.Overlap == "" {
+		options.Overlap = OverlapSkip
+	}
+	return options
+}
+
+// SetTaskScheduleOptions 设置任务的调度选项（optionsJson 为空时恢复默认）
+func (a *App) SetTaskScheduleOptions(taskID, optionsJson string) string {
+	var options *ScheduleOptions
+	if optionsJson != "" {
+		options = &ScheduleOptions{}
+		if err := json.Unmarshal([]byte(optionsJson), options); err != nil {
+			return fmt.Sprintf("数据格式错误：%v", err)
+		}
+	}
+
+	name, err := a.setTaskScheduleOptions(taskID, options)
+	if err != nil {
+		return err.Error()
+	}
+	if options == nil {
+		return fmt.Sprintf("任务 '%s' 已恢复默认调度选项", name)
+	}
+	return fmt.Sprintf("任务 '%s' 的调度选项设置成功", name)
+}
+
+// setTaskScheduleOptions 设置任务的调度选项（options 为 nil 时恢复默认），返回任务名称
+func (a *App) setTaskScheduleOptions(taskID string, options *ScheduleOptions) (string, error) {
+	if options != nil {
+		if err := options.validate(); err != nil {
+			return "", inputErrorf("错误：%v", err)
+		}
+	}
+
+	a.cacheMutex.Lock()
+
+	task, exists := a.tasksCache[taskID]
+	if !exists {
+		a.cacheMutex.Unlock()
+		return "", errTaskNotFound
+	}
+
+	timeZoneChanged := scheduleTimeZone(task.Schedule) != scheduleTimeZone(options)
+	task.Schedule = options
+	task.UpdatedAt = time.Now().Unix()
+	name := task.Name
+
+	// 保存到磁盘
+	err := a.saveTasksToDisk()
+	a.cacheMutex.Unlock()
+	if err != nil {
+		return "", fmt.Errorf("保存失败：%v", err)
+	}
+
+	// 时区变化时重新注册调度，其他选项在触发时读取
+	if timeZoneChanged {
+		if err := a.rescheduleTask(taskID); err != nil {
+			return "", fmt.Errorf("任务 '%s' 的调度选项已保存，但重新注册定时调度失败：%v", name, err)
+		}
+	}
+
+	return name, nil
+}
+
+// scheduleTimeZone 获取调度选项中的时区
+func scheduleTimeZone(options *ScheduleOptions) string {
+	if options == nil {
+		return ""
+	}
+	return options.TimeZone
+}
+
+// addCronEntry 按任务的Cron表达式和时区注册定时调度（调用方需持有cronMutex）
+func (a *App) addCronEntry(task *Task) (cron.EntryID, error) {
+	schedule, err := parseCronSchedule(task.CronExpr, a.getScheduleOptions(task).TimeZone)
+	if err != nil {
+		return 0, err
+	}
+	return a.cronScheduler.Schedule(schedule, cron.FuncJob(a.scheduledRun(task.ID))), nil
+}
+
+// scheduledRun 生成定时调度触发时执行的函数，触发时按ID读取最新的任务定义
+func (a *App) scheduledRun(taskID string) func() {
+	return func() {
+		a.cacheMutex.RLock()
+		task, exists := a.tasksCache[taskID]
+		a.cacheMutex.RUnlock()
+
+		// 任务已被删除（例如在其他进程中删除）时移除调度
+		if !exists {
+			a.removeSchedule(taskID)
+			return
+		}
+
+		// 守护进程已启动但桌面程序还未交接调度（交接按心跳间隔检查）时由守护进程执行，并立即交接避免重复执行
+		if a.schedulingDelegated() {
+			go a.handoffSchedules(true)
+			return
+		}
+
+		firedAt := time.Now()
+		options := a.getScheduleOptions(task)
+		scheduledAt := a.checkMissedTicks(task, options, firedAt)
+
+		// 生效时间范围之外的触发直接跳过（不记录任务日志，避免高频调度刷屏，只记录触发记录）
+		if location, err := loadScheduleLocation(options.TimeZone); err == nil && !options.isActive(firedAt, location) {
+			a.addScheduleFire(task.ID, scheduledAt, firedAt, "inactive", "不在生效时间范围内")
+			a.emitEvent(EventScheduleFired, ScheduleFiredEvent{TaskID: task.ID, TaskName: task.Name, FiredAt: firedAt.Format("2006-01-02 15:04:05"), Action: "inactive"})
+			return
+		}
+
+		// 随机延迟，错开同时触发的任务（应用关闭时放弃本次触发）
+		if options.Jitter > 0 {
+			select {
+			case <-time.After(time.Duration(rand.Int63n(int64(options.Jitter) * int64(time.Second)))):
+			case <-a.shutdown:
+				return
+			}
+		}
+
+		action := a.applyOverlapPolicy(task, scheduledAt)
+
+		// 正常触发不记录日志，这属于系统级别日志
+		a.emitEvent(EventScheduleFired, ScheduleFiredEvent{TaskID: task.ID, TaskName: task.Name, FiredAt: firedAt.Format("2006-01-02 15:04:05"), Action: action})
+	}
+}
+
+// applyOverlapPolicy 按任务的重叠处理方式处理一次定时触发并写入触发记录，返回采取的动作：
+// run 直接执行，queued 排队等待，skipped 跳过，replaced 停止上一次执行后重新开始
+// 未执行的触发只写入触发记录，不写入任务日志，避免挤占执行日志的保留条数
+func (a *App) applyOverlapPolicy(task *Task, scheduledAt time.Time) string {
+	policy := a.getScheduleOptions(task).Overlap
+	firedAt := time.Now()
+
+	// 先写入触发记录（不在持有taskMutex时访问数据库），确定动作后再更新
+	fireID := a.addScheduleFire(task.ID, scheduledAt, firedAt, "run", "")
+
+	a.taskMutex.Lock()
+	runs := a.runningTasks[task.ID]
+	if len(runs) == 0 || policy == OverlapAllow {
+		// 在检查运行状态的同一次加锁中登记运行，避免与手动执行或其他触发同时启动
+		progress := a.addRunLocked(task.ID)
+		a.taskMutex.Unlock()
+		go a.runScheduledTask(task, TriggerSchedule, fireID, progress)
+		return "run"
+	}
+
+	action, reason := "skipped", "上一次执行仍在进行"
+	var displaced int64
+	switch policy {
+	case OverlapQueue:
+		if _, queued := a.queuedRuns[task.ID]; queued {
+			reason = "上一次执行仍在进行，且已有排队的执行"
+		} else {
+			// 排队的执行结束后把结果写入这条触发记录
+			action, reason = "queued", "上一次执行仍在进行，排队等待"
+			a.queuedRuns[task.ID] = fireID
+		}
+
+	case OverlapCancel:
+		// 停止执行中的运行，并排队一次执行（所有运行记录结果后开始，避免同时写入进度）
+		for _, run := range runs {
+			run.cancel()
+		}
+		action, reason = "replaced", "上一次执行仍在进行，已停止上一次执行"
+		// 停止期间再次触发时由本次触发替代已排队的触发，已排队的触发记录为跳过
+		displaced = a.queuedRuns[task.ID]
+		a.queuedRuns[task.ID] = fireID
+	}
+	a.taskMutex.Unlock()
+
+	if displaced != 0 {
+		a.finishScheduleFire(displaced, "", "skipped")
+	}
+	a.setScheduleFireAction(fireID, action, reason)
+	return action
+}
+
+// runQueuedSchedule 执行排队的定时触发（progress 为上一次执行结束时登记的运行，任务已删除时撤销）
+func (a *App) runQueuedSchedule(taskID string, fireID int64, progress *TaskProgress) {
+	a.cacheMutex.RLock()
+	task, exists := a.tasksCache[taskID]
+	a.cacheMutex.RUnlock()
+
+	if !exists {
+		a.abandonRun(taskID, progress)
+		return
+	}
+
+	a.emitEvent(EventScheduleFired, ScheduleFiredEvent{TaskID: task.ID, TaskName: task.Name, FiredAt: time.Now().Format("2006-01-02 15:04:05"), Action: "run"})
+	a.runScheduledTask(task, TriggerSchedule, fireID, progress)
+}
+
+// runScheduledTask 执行定时触发的任务，并把执行日志和结果写入触发记录
+func (a *App) runScheduledTask(task *Task, trigger string, fireID int64, progress *TaskProgress) {
+	result := a.runTask(task, trigger, progress)
+	a.finishScheduleFire(fireID, result.TaskLogID, result.Status)
+}
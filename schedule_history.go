@@ -0,0 +1,366 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// 触发记录和执行时间线的数量限制
+const (
+	maxScheduleHistory     = 1000 // 每个任务保留的触发记录数
+	defaultScheduleHistory = 100  // 默认返回的触发记录数
+	maxTimelineRuns        = 5000 // 时间线最多返回的执行次数
+	maxTimelineDays        = 31   // 时间线最长的时间窗口(天)
+)
+
+// ScheduleFire 一次定时触发的记录
+type ScheduleFire struct {
+	ID          int64  `json:"id"`
+	TaskID      string `json:"taskId"`
+	ScheduledAt string `json:"scheduledAt"` // 计划触发时间
+	FiredAt     string `json:"firedAt"`     // 实际处理时间（含随机延迟）
+	Delay       int64  `json:"delay"`       // 相对计划触发时间的延迟(毫秒)
+	Action      string `json:"action"`      // run, queued, skipped, replaced, inactive, missed, catchup
+	Reason      string `json:"reason"`      // 跳过、排队或错过的原因
+	TaskLogID   string `json:"taskLogId"`   // 本次触发的执行日志ID（执行结束后才有值）
+	Status      string `json:"status"`      // 执行结果：success, partial, failed, stopped（未执行或未结束时为空）
+}
+
+// ScheduleHistory 任务的定时触发记录
+type ScheduleHistory struct {
+	TaskID string         `json:"taskId"`
+	Fires  []ScheduleFire `json:"fires"` // 按触发时间倒序
+	Error  string         `json:"error"`
+}
+
+// TimelineRun 时间线上的一次计划执行
+type TimelineRun struct {
+	Time     string `json:"time"` // 计划执行时间（本机时间，不含随机延迟）
+	TaskID   string `json:"taskId"`
+	TaskName string `json:"taskName"`
+	Jitter   int    `json:"jitter"` // 任务的随机延迟上限(秒)
+}
+
+// TimelineCluster 同一时间计划执行的多个任务
+type TimelineCluster struct {
+	Time    string   `json:"time"`
+	Count   int      `json:"count"`
+	TaskIDs []string `json:"taskIds"`
+}
+
+// ScheduleTimeline 所有定时任务在时间窗口内的计划执行
+type ScheduleTimeline struct {
+	StartTime string            `json:"startTime"`
+	EndTime   string            `json:"endTime"`
+	Runs      []TimelineRun     `json:"runs"`      // 按时间升序
+	Clusters  []TimelineCluster `json:"clusters"`  // 同一秒计划执行两个及以上任务的时间点
+	Truncated bool              `json:"truncated"` // 执行次数超过上限，只返回了前面的部分
+	Error     string            `json:"error"`
+}
+
+// GetScheduleHistory 获取任务最近的定时触发记录（包括跳过、排队和错过的触发），limit 为0时返回最近100条
+func (a *App) GetScheduleHistory(taskID string, limit int) ScheduleHistory {
+	history := ScheduleHistory{TaskID: taskID, Fires: []ScheduleFire{}}
+
+	if limit <= 0 {
+		limit = defaultScheduleHistory
+	}
+	if limit > maxScheduleHistory {
+		limit = maxScheduleHistory
+	}
+
+	fires, err := a.dbGetScheduleFires(taskID, limit)
+	if err != nil {
+		history.Error = fmt.Sprintf("查询触发记录失败：%v", err)
+		return history
+	}
+	history.Fires = append(history.Fires, fires...)
+	return history
+}
+
+// GetScheduleTimeline 获取所有已启用调度的任务在时间窗口内的计划执行，用于查看大量任务同时执行的时间点
+// startTime/endTime 格式为 "2006-01-02 15:04:05"，startTime 为空表示当前时间，endTime 为空表示 startTime 后24小时
+func (a *App) GetScheduleTimeline(startTime, endTime string) ScheduleTimeline {
+	timeline := ScheduleTimeline{
+		Runs:     []TimelineRun{},
+		Clusters: []TimelineCluster{},
+	}
+
+	start := time.Now()
+	if startTime != "" {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04:05", startTime, time.Local)
+		if err != nil {
+			timeline.Error = fmt.Sprintf("开始时间格式错误：%v", err)
+			return timeline
+		}
+		start = parsed
+	}
+
+	end := start.Add(24 * time.Hour)
+	if endTime != "" {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04:05", endTime, time.Local)
+		if err != nil {
+			timeline.Error = fmt.Sprintf("结束时间格式错误：%v", err)
+			return timeline
+		}
+		end = parsed
+	}
+
+	if !end.After(start) {
+		timeline.Error = "结束时间必须晚于开始时间"
+		return timeline
+	}
+	if end.Sub(start) > maxTimelineDays*24*time.Hour {
+		timeline.Error = fmt.Sprintf("时间窗口不能超过%d天", maxTimelineDays)
+		return timeline
+	}
+
+	timeline.StartTime = start.Format("2006-01-02 15:04:05")
+	timeline.EndTime = end.Format("2006-01-02 15:04:05")
+
+	a.cronMutex.RLock()
+	taskIDs := make([]string, 0, len(a.cronJobs))
+	for taskID := range a.cronJobs {
+		taskIDs = append(taskIDs, taskID)
+	}
+	a.cronMutex.RUnlock()
+
+	type plannedRun struct {
+		at  time.Time
+		run TimelineRun
+	}
+	var planned []plannedRun
+	for _, taskID := range taskIDs {
+		a.cacheMutex.RLock()
+		task, exists := a.tasksCache[taskID]
+		a.cacheMutex.RUnlock()
+		if !exists || task.CronExpr == "" {
+			continue
+		}
+
+		// 包含开始时间本身的执行
+		options := a.getScheduleOptions(task)
+		runTimes, err := scheduleRunTimes(task.CronExpr, options, start.Add(-time.Nanosecond), end, maxTimelineRuns+1)
+		if err != nil {
+			continue
+		}
+		for _, runTime := range runTimes {
+			planned = append(planned, plannedRun{at: runTime, run: TimelineRun{
+				Time:     runTime.Local().Format("2006-01-02 15:04:05"),
+				TaskID:   task.ID,
+				TaskName: task.Name,
+				Jitter:   options.Jitter,
+			}})
+		}
+	}
+
+	sort.Slice(planned, func(i, j int) bool {
+		if !planned[i].at.Equal(planned[j].at) {
+			return planned[i].at.Before(planned[j].at)
+		}
+		return planned[i].run.TaskName < planned[j].run.TaskName
+	})
+	if len(planned) > maxTimelineRuns {
+		planned = planned[:maxTimelineRuns]
+		timeline.Truncated = true
+	}
+
+	for i, p := range planned {
+		timeline.Runs = append(timeline.Runs, p.run)
+
+		// 同一秒的执行已按时间排在一起
+		if i > 0 && p.at.Equal(planned[i-1].at) {
+			last := len(timeline.Clusters) - 1
+			if last >= 0 && timeline.Clusters[last].Time == p.run.Time {
+				timeline.Clusters[last].Count++
+				timeline.Clusters[last].TaskIDs = append(timeline.Clusters[last].TaskIDs, p.run.TaskID)
+			} else {
+				timeline.Clusters = append(timeline.Clusters, TimelineCluster{
+					Time:    p.run.Time,
+					Count:   2,
+					TaskIDs: []string{planned[i-1].run.TaskID, p.run.TaskID},
+				})
+			}
+		}
+	}
+
+	return timeline
+}
+
+// addScheduleFire 写入一条定时触发记录，返回记录ID（写入失败时为0）
+func (a *App) addScheduleFire(taskID string, scheduledAt, firedAt time.Time, action, reason string) int64 {
+	id, err := a.dbInsertScheduleFire(taskID, scheduledAt, firedAt, action, reason)
+	if err != nil {
+		fmt.Printf("写入定时触发记录失败: %v\n", err)
+		return 0
+	}
+	return id
+}
+
+// finishScheduleFire 把执行日志和执行结果写入触发记录
+func (a *App) finishScheduleFire(id int64, taskLogID, status string) {
+	if id == 0 {
+		return
+	}
+	if err := a.dbUpdateScheduleFire(id, taskLogID, status); err != nil {
+		fmt.Printf("更新定时触发记录失败: %v\n", err)
+	}
+}
+
+// setScheduleFireAction 更新触发记录采取的动作和原因
+func (a *App) setScheduleFireAction(id int64, action, reason string) {
+	if id == 0 {
+		return
+	}
+	if err := a.dbUpdateScheduleFireAction(id, action, reason); err != nil {
+		fmt.Printf("更新定时触发记录失败: %v\n", err)
+	}
+}
+
+// dbInsertScheduleFire 插入定时触发记录，并只保留任务最近的 maxScheduleHistory 条记录
+func (a *App) dbInsertScheduleFire(taskID string, scheduledAt, firedAt time.Time, action, reason string) (int64, error) {
+	a.dbMutex.Lock()
+	defer a.dbMutex.Unlock()
+
+	result, err := a.db.Exec("INSERT INTO schedule_history (task_id, scheduled_at, fired_at, action, reason) VALUES (?, ?, ?, ?, ?)",
+		taskID, scheduledAt.UnixMilli(), firedAt.UnixMilli(), action, reason)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = a.db.Exec(`DELETE FROM schedule_history WHERE task_id = ? AND id NOT IN
+		(SELECT id FROM schedule_history WHERE task_id = ? ORDER BY id DESC LIMIT ?)`, taskID, taskID, maxScheduleHistory)
+	if err != nil {
+		fmt.Printf("清理定时触发记录失败: %v\n", err)
+	}
+	return id, nil
+}
+
+// dbUpdateScheduleFire 更新触发记录的执行日志ID和执行结果
+func (a *App) dbUpdateScheduleFire(id int64, taskLogID, status string) error {
+	a.dbMutex.Lock()
+	defer a.dbMutex.Unlock()
+
+	_, err := a.db.Exec("UPDATE schedule_history SET task_log_id = ?, status = ? WHERE id = ?", taskLogID, status, id)
+	return err
+}
+
+// dbUpdateScheduleFireAction 更新触发记录的动作和原因
+func (a *App) dbUpdateScheduleFireAction(id int64, action, reason string) error {
+	a.dbMutex.Lock()
+	defer a.dbMutex.Unlock()
+
+	_, err := a.db.Exec("UPDATE schedule_history SET action = ?, reason = ? WHERE id = ?", action, reason, id)
+	return err
+}
+
+// dbGetScheduleFires 查询任务最近的触发记录（按触发时间倒序）
+func (a *App) dbGetScheduleFires(taskID string, limit int) ([]ScheduleFire, error) {
+	a.dbMutex.RLock()
+	defer a.dbMutex.RUnlock()
+
+	query := `
+	SELECT id, task_id, scheduled_at, fired_at, action, reason, task_log_id, status
+	FROM schedule_history
+	WHERE task_id = ?
+	ORDER BY id DESC
+	LIMIT ?
+	`
+
+	rows, err := a.db.Query(query, taskID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fires []ScheduleFire
+	for rows.Next() {
+		var fire ScheduleFire
+		var scheduledAt, firedAt int64
+		if err := rows.Scan(&fire.ID, &fire.TaskID, &scheduledAt, &firedAt, &fire.Action, &fire.Reason, &fire.TaskLogID, &fire.Status); err != nil {
+			return nil, err
+		}
+		fire.ScheduledAt = time.UnixMilli(scheduledAt).Format("2006-01-02 15:04:05")
+		fire.FiredAt = time.UnixMilli(firedAt).Format("2006-01-02 15:04:05")
+		fire.Delay = firedAt - scheduledAt
+		fires = append(fires, fire)
+	}
+	return fires, rows.Err()
+}
+
+// pruneScheduleHistory 按各任务保留策略的保留天数清理触发记录，返回删除的条数（条数由 maxScheduleHistory 限制）
+func (a *App) pruneScheduleHistory() int {
+	taskIDs, err := a.dbGetScheduleHistoryTaskIDs()
+	if err != nil {
+		fmt.Printf("查询定时触发记录失败: %v\n", err)
+		return 0
+	}
+
+	removed := 0
+	for _, taskID := range taskIDs {
+		policy := a.getRetentionPolicy(taskID)
+		if policy.MaxAgeDays <= 0 {
+			continue
+		}
+		count, err := a.dbPruneScheduleFires(taskID, time.Now().AddDate(0, 0, -policy.MaxAgeDays))
+		if err != nil {
+			fmt.Printf("清理定时触发记录失败: %v\n", err)
+			continue
+		}
+		removed += count
+	}
+	return removed
+}
+
+// dbGetScheduleHistoryTaskIDs 查询有触发记录的任务ID
+func (a *App) dbGetScheduleHistoryTaskIDs() ([]string, error) {
+	a.dbMutex.RLock()
+	defer a.dbMutex.RUnlock()
+
+	rows, err := a.db.Query("SELECT DISTINCT task_id FROM schedule_history")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var taskIDs []string
+	for rows.Next() {
+		var taskID string
+		if err := rows.Scan(&taskID); err != nil {
+			return taskIDs, err
+		}
+		taskIDs = append(taskIDs, taskID)
+	}
+	return taskIDs, rows.Err()
+}
+
+// dbPruneScheduleFires 删除任务在 cutoff 之前的触发记录，返回删除的条数
+func (a *App) dbPruneScheduleFires(taskID string, cutoff time.Time) (int, error) {
+	a.dbMutex.Lock()
+	defer a.dbMutex.Unlock()
+
+	result, err := a.db.Exec("DELETE FROM schedule_history WHERE task_id = ? AND fired_at < ?", taskID, cutoff.UnixMilli())
+	if err != nil {
+		return 0, err
+	}
+	count, _ := result.RowsAffected()
+	return int(count), nil
+}
+
+// dbDeleteScheduleFires 删除任务的触发记录（taskID 为 "all" 时删除所有记录）
+func (a *App) dbDeleteScheduleFires(taskID string) error {
+	a.dbMutex.Lock()
+	defer a.dbMutex.Unlock()
+
+	if taskID == "all" {
+		_, err := a.db.Exec("DELETE FROM schedule_history")
+		return err
+	}
+	_, err := a.db.Exec("DELETE FROM schedule_history WHERE task_id = ?", taskID)
+	return err
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// httpService 可按设置启停的后台HTTP服务（本地REST接口、指标接口）
+type httpService struct {
+	name   string
+	mutex  sync.Mutex
+	server *http.Server
+	active bool   // 当前进程是否提供该服务（命令行模式不提供）
+	err    string // 最近一次启动失败的原因
+}
+
+// start 启动服务，enabled 为 false 时只记录当前进程提供该服务
+func (s *httpService) start(enabled bool, address string, handler func() http.Handler) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.active = true
+	previousErr := s.err
+	s.err = ""
+
+	if !enabled || s.server != nil {
+		return
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		s.err = fmt.Sprintf("监听 %s 失败：%v", address, err)
+		if s.err != previousErr {
+			// 重试启动时不重复输出相同的错误
+			fmt.Printf("启动%s失败: %v\n", s.name, err)
+		}
+		return
+	}
+
+	server := &http.Server{
+		Handler:           handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	s.server = server
+
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			fmt.Printf("%s异常退出: %v\n", s.name, err)
+		}
+	}()
+}
+
+// stop 停止服务
+func (s *httpService) stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.server == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		fmt.Printf("停止%s失败: %v\n", s.name, err)
+	}
+	s.server = nil
+}
+
+// isActive 判断当前进程是否提供该服务
+func (s *httpService) isActive() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.active
+}
+
+// status 获取运行状态和最近一次启动失败的原因
+func (s *httpService) status() (bool, string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.server != nil, s.err
+}
+
+// startHTTPServices 按设置启动本地REST接口和指标接口
+func (a *App) startHTTPServices() {
+	a.startAPIServer()
+	a.startMetricsServer()
+}
+
+// stopHTTPServices 停止本地REST接口和指标接口
+func (a *App) stopHTTPServices() {
+	a.apiService.stop()
+	a.metricsService.stop()
+}
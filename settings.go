@@ -0,0 +1,159 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+// AppSettings 全局设置（以JSON形式保存在SQLite的app_settings表中）
+type AppSettings struct {
+	Retention              RetentionPolicy    `json:"retention"`              // 全局日志保留策略
+	CleanupIntervalMinutes int                `json:"cleanupIntervalMinutes"` // 后台清理间隔（分钟）
+	Capture                CaptureConfig      `json:"capture"`                // 全局请求/响应采集配置
+	API                    APISettings        `json:"api"`                    // 本地REST接口配置
+	Metrics                MetricsSettings    `json:"metrics"`                // Prometheus指标接口配置
+	Notifications          NotificationConfig `json:"notifications"`          // 全局通知配置（任务未单独设置时使用）
+}
+
+// defaultSettings 默认设置
+func defaultSettings() AppSettings {
+	return AppSettings{
+		Retention:              defaultRetentionPolicy(),
+		CleanupIntervalMinutes: 60,
+		Capture:                defaultCaptureConfig(),
+		API:                    defaultAPISettings(),
+		Metrics:                defaultMetricsSettings(),
+	}
+}
+
+// loadSettings 从数据库加载设置，缺失的字段使用默认值
+func (a *App) loadSettings() {
+	settings := defaultSettings()
+
+	value, err := a.dbGetSetting("settings")
+	if err == nil {
+		if err := json.Unmarshal([]byte(value), &settings); err != nil {
+			fmt.Printf("解析设置失败，使用默认设置: %v\n", err)
+			settings = defaultSettings()
+		}
+	} else if err != sql.ErrNoRows {
+		fmt.Printf("读取设置失败，使用默认设置: %v\n", err)
+	}
+
+	a.settingsMutex.Lock()
+	a.settings = settings
+	a.settingsMutex.Unlock()
+}
+
+// getSettings 获取当前设置的副本
+func (a *App) getSettings() AppSettings {
+	a.settingsMutex.RLock()
+	defer a.settingsMutex.RUnlock()
+	return a.settings
+}
+
+// GetSettings 获取全局设置
+func (a *App) GetSettings() AppSettings {
+	return a.getSettings()
+}
+
+// SaveSettings 保存全局设置
+func (a *App) SaveSettings(settingsJson string) string {
+	settings := a.getSettings()
+	if err := json.Unmarshal([]byte(settingsJson), &settings); err != nil {
+		return fmt.Sprintf("数据格式错误：%v", err)
+	}
+
+	if err := a.applySettings(settings); err != nil {
+		return fmt.Sprintf("错误：%v", err)
+	}
+
+	return "设置保存成功"
+}
+
+// validate 校验设置
+func (s AppSettings) validate() error {
+	if err := s.Retention.validate(); err != nil {
+		return err
+	}
+	if err := s.Capture.validate(); err != nil {
+		return err
+	}
+	if err := s.API.validate(); err != nil {
+		return err
+	}
+	if err := s.Metrics.validate(); err != nil {
+		return err
+	}
+	if err := s.Notifications.validate(); err != nil {
+		return err
+	}
+	if s.CleanupIntervalMinutes <= 0 {
+		return fmt.Errorf("清理间隔必须大于0分钟")
+	}
+	return nil
+}
+
+// applySettings 校验并保存设置
+func (a *App) applySettings(settings AppSettings) error {
+	// 启用接口时自动生成令牌
+	if settings.API.Enabled && settings.API.Token == "" {
+		token, err := generateAPIToken()
+		if err != nil {
+			return fmt.Errorf("生成接口令牌失败：%v", err)
+		}
+		settings.API.Token = token
+	}
+
+	if err := settings.validate(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("保存失败：%v", err)
+	}
+	if err := a.dbSetSetting("settings", string(data)); err != nil {
+		return fmt.Errorf("保存失败：%v", err)
+	}
+
+	a.settingsMutex.Lock()
+	previous := a.settings
+	a.settings = settings
+	a.settingsMutex.Unlock()
+
+	// 端口或开关变化时重启接口服务
+	if previous.API.Enabled != settings.API.Enabled || previous.API.Port != settings.API.Port {
+		a.reloadAPIServer()
+	}
+	if previous.Metrics.Enabled != settings.Metrics.Enabled || previous.Metrics.Address != settings.Metrics.Address {
+		a.reloadMetricsServer()
+	}
+
+	return nil
+}
+
+// dbGetSetting 从数据库获取设置项
+func (a *App) dbGetSetting(key string) (string, error) {
+	a.dbMutex.RLock()
+	defer a.dbMutex.RUnlock()
+
+	var value string
+	err := a.db.QueryRow("SELECT value FROM app_settings WHERE key = ?", key).Scan(&value)
+	return value, err
+}
+
+// dbSetSetting 在数据库中保存设置项
+func (a *App) dbSetSetting(key, value string) error {
+	a.dbMutex.Lock()
+	defer a.dbMutex.Unlock()
+
+	query := `
+	INSERT OR REPLACE INTO app_settings (key, value, updated_at)
+	VALUES (?, ?, CURRENT_TIMESTAMP)
+	`
+
+	_, err := a.db.Exec(query, key, value)
+	return err
+}
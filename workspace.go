@@ -0,0 +1,581 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// 工作区文件格式标识和当前版本
+const (
+	workspaceFormat  = "httptaskrunner-workspace"
+	workspaceVersion = 1
+)
+
+// WorkspaceBundle 工作区导出文件
+type WorkspaceBundle struct {
+	Format             string                     `json:"format"`
+	Version            int                        `json:"version"`
+	AppVersion         string                     `json:"appVersion"`
+	ExportedAt         string                     `json:"exportedAt"`
+	Tasks              []*Task                    `json:"tasks"`
+	Tags               []string                   `json:"tags"`
+	Variables          map[string]EnvVariableData `json:"variables,omitempty"`
+	VariablesRedacted  bool                       `json:"variablesRedacted,omitempty"`  // 只导出了变量名，没有变量值
+	EncryptedVariables *WorkspaceEncryptedData    `json:"encryptedVariables,omitempty"` // 加密的环境变量
+	Schedules          []string                   `json:"schedules"`                    // 已启用定时调度的任务ID
+	Settings           *AppSettings               `json:"settings,omitempty"`
+}
+
+// WorkspaceEncryptedData 使用密码加密的数据（scrypt派生密钥 + AES-256-GCM）
+type WorkspaceEncryptedData struct {
+	KDF   string `json:"kdf"`
+	Salt  string `json:"salt"`
+	Nonce string `json:"nonce"`
+	Data  string `json:"data"`
+}
+
+// WorkspaceExportOptions 工作区导出选项
+type WorkspaceExportOptions struct {
+	TaskIDs         []string `json:"taskIds"`         // 为空时导出全部任务
+	Variables       string   `json:"variables"`       // 环境变量导出方式: none, names（默认，只导出变量名）, plain, encrypted
+	Password        string   `json:"password"`        // variables 为 encrypted 时使用的密码
+	IncludeSettings bool     `json:"includeSettings"` // 是否导出全局设置
+}
+
+// WorkspaceImportOptions 工作区导入选项
+type WorkspaceImportOptions struct {
+	Strategy        string `json:"strategy"`        // ID或名称冲突时的处理方式: skip（默认）, overwrite, keep_both
+	DryRun          bool   `json:"dryRun"`          // 只生成变更报告，不修改数据
+	Password        string `json:"password"`        // 解密环境变量的密码
+	ImportSettings  bool   `json:"importSettings"`  // 是否导入全局设置
+	ImportSchedules bool   `json:"importSchedules"` // 是否恢复定时调度
+}
+
+// WorkspaceChange 导入时的单项变更
+type WorkspaceChange struct {
+	Kind         string `json:"kind"`         // task, variable, settings, schedule
+	Name         string `json:"name"`         // 任务名称或变量名
+	ID           string `json:"id"`           // 导入后的任务ID
+	Action       string `json:"action"`       // create, overwrite, rename, skip
+	ConflictWith string `json:"conflictWith"` // 冲突的本地任务ID或变量名
+	Reason       string `json:"reason"`
+}
+
+// WorkspaceImportReport 工作区导入报告
+type WorkspaceImportReport struct {
+	Source      string            `json:"source"`
+	DryRun      bool              `json:"dryRun"`
+	Version     int               `json:"version"`
+	Changes     []WorkspaceChange `json:"changes"`
+	Created     int               `json:"created"`
+	Overwritten int               `json:"overwritten"`
+	Renamed     int               `json:"renamed"`
+	Skipped     int               `json:"skipped"`
+	Warnings    []string          `json:"warnings"`
+	Message     string            `json:"message"`
+	Error       string            `json:"error"`
+}
+
+// ExportWorkspace 导出工作区（任务、标签、环境变量、定时调度和设置）
+// optionsJson 为 WorkspaceExportOptions 的JSON，path 为空时弹出保存对话框
+func (a *App) ExportWorkspace(optionsJson, path string) string {
+	var options WorkspaceExportOptions
+	if optionsJson != "" {
+		if err := json.Unmarshal([]byte(optionsJson), &options); err != nil {
+			return fmt.Sprintf("选项格式错误：%v", err)
+		}
+	}
+	if options.Variables == "" {
+		options.Variables = "names"
+	}
+
+	bundle, err := a.buildWorkspaceBundle(options)
+	if err != nil {
+		return fmt.Sprintf("错误：%v", err)
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("导出失败：%v", err)
+	}
+
+	defaultName := fmt.Sprintf("workspace_%s.json", time.Now().Format("20060102_150405"))
+	path, err = a.resolveExportPath(path, defaultName, "工作区文件 (*.json)", "*.json")
+	if err != nil {
+		return fmt.Sprintf("错误：%v", err)
+	}
+
+	if err := writeExportFile(path, data); err != nil {
+		return fmt.Sprintf("导出失败：%v", err)
+	}
+
+	return fmt.Sprintf("已导出 %d 个任务到 %s", len(bundle.Tasks), path)
+}
+
+// ImportWorkspace 导入工作区文件，optionsJson 为 WorkspaceImportOptions 的JSON
+// dryRun 为 true 时只返回将要发生的变更；path 为空时弹出文件选择对话框
+func (a *App) ImportWorkspace(path, optionsJson string) WorkspaceImportReport {
+	report := WorkspaceImportReport{Changes: []WorkspaceChange{}, Warnings: []string{}}
+
+	var options WorkspaceImportOptions
+	if optionsJson != "" {
+		if err := json.Unmarshal([]byte(optionsJson), &options); err != nil {
+			report.Error = fmt.Sprintf("选项格式错误：%v", err)
+			return report
+		}
+	}
+	switch options.Strategy {
+	case "":
+		options.Strategy = "skip"
+	case "skip", "overwrite", "keep_both":
+	default:
+		report.Error = fmt.Sprintf("不支持的冲突处理方式：%s", options.Strategy)
+		return report
+	}
+	report.DryRun = options.DryRun
+
+	path, data, err := a.readImportFile(path, "选择工作区文件", "工作区文件 (*.json)", "*.json")
+	report.Source = path
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+
+	var bundle WorkspaceBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		report.Error = fmt.Sprintf("解析工作区文件失败：%v", err)
+		return report
+	}
+	if bundle.Format != workspaceFormat {
+		report.Error = "不是有效的工作区文件"
+		return report
+	}
+	if bundle.Version > workspaceVersion {
+		report.Error = fmt.Sprintf("工作区文件版本 %d 高于当前支持的版本 %d，请升级应用", bundle.Version, workspaceVersion)
+		return report
+	}
+	report.Version = bundle.Version
+
+	variables := bundle.Variables
+	if bundle.EncryptedVariables != nil {
+		if options.Password == "" {
+			report.Error = "工作区中的环境变量已加密，请提供密码"
+			return report
+		}
+		variables, err = decryptWorkspaceVariables(bundle.EncryptedVariables, options.Password)
+		if err != nil {
+			report.Error = err.Error()
+			return report
+		}
+	}
+
+	if err := a.importWorkspace(&report, &bundle, variables, options); err != nil {
+		report.Error = err.Error()
+		return report
+	}
+
+	prefix := "导入完成"
+	if options.DryRun {
+		prefix = "预览（未修改数据）"
+	}
+	report.Message = fmt.Sprintf("%s：新建 %d 项，覆盖 %d 项，重命名 %d 项，跳过 %d 项",
+		prefix, report.Created, report.Overwritten, report.Renamed, report.Skipped)
+	return report
+}
+
+// buildWorkspaceBundle 根据导出选项生成工作区数据
+func (a *App) buildWorkspaceBundle(options WorkspaceExportOptions) (*WorkspaceBundle, error) {
+	tasks, err := a.tasksForExport(options.TaskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &WorkspaceBundle{
+		Format:     workspaceFormat,
+		Version:    workspaceVersion,
+		AppVersion: AppVersion,
+		ExportedAt: time.Now().Format("2006-01-02 15:04:05"),
+		Tasks:      make([]*Task, 0, len(tasks)),
+		Tags:       []string{},
+		Schedules:  []string{},
+	}
+
+	tags := make(map[string]bool)
+	exported := make(map[string]bool)
+	for _, task := range tasks {
+		// 运行状态不属于工作区配置
+		copied := *task
+		copied.IsRunning = false
+		if task.Notifications != nil {
+			notifications := task.Notifications.withoutSecrets()
+			copied.Notifications = &notifications
+		}
+		bundle.Tasks = append(bundle.Tasks, &copied)
+		exported[task.ID] = true
+		for _, tag := range task.Tags {
+			tags[tag] = true
+		}
+	}
+	for tag := range tags {
+		bundle.Tags = append(bundle.Tags, tag)
+	}
+	sort.Strings(bundle.Tags)
+
+	for _, taskID := range a.GetScheduledTasks() {
+		if exported[taskID] {
+			bundle.Schedules = append(bundle.Schedules, taskID)
+		}
+	}
+	sort.Strings(bundle.Schedules)
+
+	if options.Variables != "none" {
+		variables, err := a.dbGetAllEnvVariables()
+		if err != nil {
+			return nil, fmt.Errorf("读取环境变量失败：%v", err)
+		}
+
+		switch options.Variables {
+		case "names":
+			bundle.VariablesRedacted = true
+			bundle.Variables = make(map[string]EnvVariableData, len(variables))
+			for key, value := range variables {
+				bundle.Variables[key] = EnvVariableData{Separator: value.Separator}
+			}
+		case "plain":
+			bundle.Variables = variables
+		case "encrypted":
+			if options.Password == "" {
+				return nil, fmt.Errorf("加密环境变量需要设置密码")
+			}
+			bundle.EncryptedVariables, err = encryptWorkspaceVariables(variables, options.Password)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("不支持的环境变量导出方式：%s", options.Variables)
+		}
+	}
+
+	if options.IncludeSettings {
+		settings := a.getSettings()
+		// 接口令牌只在本机有效，通知地址和密钥可直接用于发送消息，都不随工作区导出
+		settings.API.Token = ""
+		settings.Notifications = settings.Notifications.withoutSecrets()
+		bundle.Settings = &settings
+	}
+
+	return bundle, nil
+}
+
+// importWorkspace 按导入选项合并工作区数据，DryRun 时只记录变更
+func (a *App) importWorkspace(report *WorkspaceImportReport, bundle *WorkspaceBundle, variables map[string]EnvVariableData, options WorkspaceImportOptions) error {
+	record := func(change WorkspaceChange) {
+		report.Changes = append(report.Changes, change)
+		switch change.Action {
+		case "create":
+			report.Created++
+		case "overwrite":
+			report.Overwritten++
+		case "rename":
+			report.Renamed++
+		case "skip":
+			report.Skipped++
+		}
+	}
+
+	// 任务
+	now := time.Now().Unix()
+	finalIDs := make(map[string]string) // 工作区中的任务ID -> 导入后的任务ID
+	var cronChanged []string            // 被覆盖且Cron表达式变化的任务（已调度时需要重新注册）
+
+	localSettings := a.getSettings()
+	a.cacheMutex.Lock()
+	names := make(map[string]string, len(a.tasksCache))
+	for id, task := range a.tasksCache {
+		names[task.Name] = id
+	}
+
+	for _, source := range bundle.Tasks {
+		if source == nil || source.ID == "" {
+			continue
+		}
+		task := *source
+		task.IsRunning = false
+		if task.Tags == nil {
+			task.Tags = []string{}
+		}
+
+		conflictID := ""
+		reason := ""
+		if _, exists := a.tasksCache[task.ID]; exists {
+			conflictID = task.ID
+			reason = "任务ID已存在"
+		} else if id, exists := names[task.Name]; exists {
+			conflictID = id
+			reason = "任务名称已存在"
+		}
+
+		change := WorkspaceChange{Kind: "task", Name: task.Name, ID: task.ID, ConflictWith: conflictID, Reason: reason}
+		switch {
+		case conflictID == "":
+			change.Action = "create"
+		case options.Strategy == "skip":
+			change.Action = "skip"
+			change.ID = conflictID
+		case options.Strategy == "overwrite":
+			change.Action = "overwrite"
+			task.ID = conflictID
+			task.CreatedAt = a.tasksCache[conflictID].CreatedAt
+			task.UpdatedAt = now
+			if task.CronExpr != a.tasksCache[conflictID].CronExpr {
+				cronChanged = append(cronChanged, conflictID)
+			}
+		default:
+			change.Action = "rename"
+			if _, exists := a.tasksCache[task.ID]; exists {
+				task.ID = newTaskID()
+			}
+			if _, exists := names[task.Name]; exists {
+				task.Name = uniqueTaskName(task.Name, names)
+			}
+			task.CreatedAt = now
+			task.UpdatedAt = now
+		}
+		if task.Notifications != nil && change.Action != "skip" {
+			// 通知地址和密钥不随工作区导出，从覆盖的本地任务或全局设置中恢复
+			locals := []NotificationConfig{localSettings.Notifications}
+			if conflictID != "" && a.tasksCache[conflictID].Notifications != nil {
+				locals = append([]NotificationConfig{*a.tasksCache[conflictID].Notifications}, locals...)
+			}
+			notifications, warnings := task.Notifications.restoreSecrets(locals...)
+			task.Notifications = &notifications
+			for _, warning := range warnings {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("任务 '%s'：%s", task.Name, warning))
+			}
+		}
+		change.ID = task.ID
+		if change.Action == "rename" {
+			change.Name = task.Name
+		}
+		finalIDs[source.ID] = task.ID
+		if change.Action != "skip" {
+			if !options.DryRun {
+				a.tasksCache[task.ID] = &task
+			}
+			names[task.Name] = task.ID
+		}
+		record(change)
+	}
+
+	var saveErr error
+	if !options.DryRun {
+		saveErr = a.saveTasksToDisk()
+	}
+	a.cacheMutex.Unlock()
+	if saveErr != nil {
+		return fmt.Errorf("保存任务失败：%v", saveErr)
+	}
+	if !options.DryRun {
+		for _, taskID := range cronChanged {
+			if err := a.rescheduleTask(taskID); err != nil {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("重新注册任务 %s 的定时调度失败：%v", taskID, err))
+			}
+		}
+	}
+
+	// 环境变量（变量通过名称被任务引用，不能重命名）
+	if len(variables) > 0 {
+		existing, err := a.dbGetAllEnvVariables()
+		if err != nil {
+			return fmt.Errorf("读取环境变量失败：%v", err)
+		}
+
+		keys := make([]string, 0, len(variables))
+		for key := range variables {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			change := WorkspaceChange{Kind: "variable", Name: key}
+			_, exists := existing[key]
+			switch {
+			case !exists:
+				change.Action = "create"
+				if bundle.VariablesRedacted {
+					change.Reason = "工作区只包含变量名，已创建空变量"
+				}
+			case bundle.VariablesRedacted:
+				change.Action = "skip"
+				change.ConflictWith = key
+				change.Reason = "工作区只包含变量名，保留本地值"
+			case options.Strategy == "overwrite":
+				change.Action = "overwrite"
+				change.ConflictWith = key
+			default:
+				change.Action = "skip"
+				change.ConflictWith = key
+				change.Reason = "变量已存在"
+			}
+
+			if change.Action != "skip" && !options.DryRun {
+				if err := a.dbSetEnvVariable(key, variables[key]); err != nil {
+					report.Warnings = append(report.Warnings, fmt.Sprintf("导入变量 '%s' 失败：%v", key, err))
+					continue
+				}
+			}
+			record(change)
+		}
+	}
+
+	// 全局设置
+	if bundle.Settings != nil {
+		if options.ImportSettings {
+			bundle.Settings.API.Token = localSettings.API.Token
+			notifications, warnings := bundle.Settings.Notifications.restoreSecrets(localSettings.Notifications)
+			bundle.Settings.Notifications = notifications
+			for _, warning := range warnings {
+				report.Warnings = append(report.Warnings, "全局设置："+warning)
+			}
+			if err := bundle.Settings.validate(); err != nil {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("工作区中的设置无效，未导入：%v", err))
+			} else {
+				if !options.DryRun {
+					if err := a.applySettings(*bundle.Settings); err != nil {
+						return fmt.Errorf("导入设置失败：%v", err)
+					}
+				}
+				record(WorkspaceChange{Kind: "settings", Name: "settings", Action: "overwrite"})
+			}
+		} else {
+			record(WorkspaceChange{Kind: "settings", Name: "settings", Action: "skip", Reason: "未选择导入设置"})
+		}
+	}
+
+	// 定时调度
+	if options.ImportSchedules {
+		skipped := make(map[string]bool)
+		for _, change := range report.Changes {
+			if change.Kind == "task" && change.Action == "skip" {
+				skipped[change.ID] = true
+			}
+		}
+
+		for _, sourceID := range bundle.Schedules {
+			taskID, exists := finalIDs[sourceID]
+			if !exists {
+				continue
+			}
+			change := WorkspaceChange{Kind: "schedule", Name: sourceID, ID: taskID, Action: "create"}
+			if skipped[taskID] {
+				change.Action = "skip"
+				change.Reason = "任务未导入"
+			} else if !options.DryRun {
+				if _, err := a.scheduleTask(taskID); err != nil {
+					report.Warnings = append(report.Warnings, fmt.Sprintf("恢复任务 %s 的定时调度失败：%v", taskID, err))
+					continue
+				}
+			}
+			record(change)
+		}
+	}
+
+	return nil
+}
+
+// uniqueTaskName 生成不与已有任务重名的名称
+func uniqueTaskName(name string, names map[string]string) string {
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s (%d)", name, i)
+		if _, exists := names[candidate]; !exists {
+			return candidate
+		}
+	}
+}
+
+// encryptWorkspaceVariables 使用密码加密环境变量
+func encryptWorkspaceVariables(variables map[string]EnvVariableData, password string) (*WorkspaceEncryptedData, error) {
+	plaintext, err := json.Marshal(variables)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := workspaceCipher(password, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return &WorkspaceEncryptedData{
+		KDF:   "scrypt",
+		Salt:  base64.StdEncoding.EncodeToString(salt),
+		Nonce: base64.StdEncoding.EncodeToString(nonce),
+		Data:  base64.StdEncoding.EncodeToString(gcm.Seal(nil, nonce, plaintext, nil)),
+	}, nil
+}
+
+// decryptWorkspaceVariables 使用密码解密环境变量
+func decryptWorkspaceVariables(encrypted *WorkspaceEncryptedData, password string) (map[string]EnvVariableData, error) {
+	if encrypted.KDF != "scrypt" {
+		return nil, fmt.Errorf("不支持的密钥派生方式：%s", encrypted.KDF)
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(encrypted.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("加密数据已损坏")
+	}
+	nonce, err := base64.StdEncoding.DecodeString(encrypted.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("加密数据已损坏")
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(encrypted.Data)
+	if err != nil {
+		return nil, fmt.Errorf("加密数据已损坏")
+	}
+
+	gcm, err := workspaceCipher(password, salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("加密数据已损坏")
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("密码错误或加密数据已损坏")
+	}
+
+	var variables map[string]EnvVariableData
+	if err := json.Unmarshal(plaintext, &variables); err != nil {
+		return nil, fmt.Errorf("解析环境变量失败：%v", err)
+	}
+	return variables, nil
+}
+
+// workspaceCipher 根据密码和盐派生AES-256-GCM密钥
+func workspaceCipher(password string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(password), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}